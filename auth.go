@@ -0,0 +1,355 @@
+package banjo
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PrincipalKey is Context store key of authenticated principal
+const PrincipalKey = "banjo.principal"
+
+// PasswordIterations is PBKDF2 iteration count used by HashPassword
+const PasswordIterations = 600000
+
+// passwordScheme is prefix of password hashes created by HashPassword
+const passwordScheme = "pbkdf2-sha256"
+
+// maxPasswordIterations limits iteration count accepted by VerifyPassword
+const maxPasswordIterations = 10000000
+
+// invalidTokenDescription is RFC 6750 error description
+// sent when Bearer token is rejected
+const invalidTokenDescription = "The access token is invalid or expired"
+
+// BasicAuth function
+//
+// Returns middleware requiring HTTP Basic credentials
+// accepted by validate, username is stored as principal,
+// 401 with WWW-Authenticate challenge is sent otherwise
+//
+// Params:
+// - realm    {string}
+// - validate {func(username, password string) bool}
+//
+// Response:
+// - middleware {Middleware}
+//
+func BasicAuth(realm string, validate func(username string, password string) bool) Middleware {
+	challenge := "Basic realm=" + strconv.Quote(realm) + ", charset=\"UTF-8\""
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			username, password, ok := basicCredentials(ctx.Request.Header("Authorization"))
+
+			if !ok || !validate(username, password) {
+				unauthorized(ctx, challenge)
+				return
+			}
+
+			ctx.Set(PrincipalKey, username)
+			next(ctx)
+		}
+	}
+}
+
+// BasicAuthUsers function
+//
+// Same as BasicAuth, but validates credentials by map
+// of usernames to password hashes created by HashPassword,
+// use BasicAuth with own validator for bcrypt or argon2 hashes
+// Example usage:
+// hash, err := banjo.HashPassword("secret")
+// users := map[string]string{"admin": hash}
+// app.Get("/admin", banjo.Chain(admin, banjo.BasicAuthUsers("admin", users)))
+//
+// Params:
+// - realm {string}
+// - users {map[string]string} username => password hash
+//
+// Response:
+// - middleware {Middleware}
+//
+func BasicAuthUsers(realm string, users map[string]string) Middleware {
+	// unknown users are checked against dummy hash,
+	// so response time doesn't reveal which usernames exist
+	dummy := passwordScheme + "$" + strconv.Itoa(PasswordIterations) + "$AAAAAAAAAAAAAAAAAAAAAA$AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+
+	return BasicAuth(realm, func(username string, password string) bool {
+		expected, ok := users[username]
+		if !ok {
+			expected = dummy
+		}
+
+		match := VerifyPassword(password, expected)
+
+		return ok && match
+	})
+}
+
+// HashPassword function
+//
+// Returns salted PBKDF2-HMAC-SHA256 hash for BasicAuthUsers
+// in "pbkdf2-sha256$iterations$salt$key" format,
+// salt & key are base64 encoded
+//
+// Params:
+// - password {string}
+//
+// Response:
+// - hash {string}
+// - err  {error} random source error
+//
+func HashPassword(password string) (string, error) {
+	return hashPassword(password, PasswordIterations)
+}
+
+// VerifyPassword function
+//
+// Checks password against hash created by HashPassword
+// in constant time
+//
+// Params:
+// - password {string}
+// - hash     {string}
+//
+// Response:
+// - ok {bool} false for wrong password or malformed hash
+//
+func VerifyPassword(password string, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 || iterations > maxPasswordIterations {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, iterations, len(expected))
+
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+// hashPassword function
+//
+// Returns password hash with given iteration count
+//
+// Params:
+// - password   {string}
+// - iterations {int}
+//
+// Response:
+// - hash {string}
+// - err  {error} random source error
+//
+func hashPassword(password string, iterations int) (string, error) {
+	salt := make([]byte, 16)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, iterations, sha256.Size)
+	encoding := base64.RawStdEncoding
+
+	return fmt.Sprintf("%s$%d$%s$%s", passwordScheme, iterations, encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// pbkdf2SHA256 function
+//
+// Derives key with PBKDF2 (RFC 8018) using HMAC-SHA256
+//
+// Params:
+// - password   {[]byte}
+// - salt       {[]byte}
+// - iterations {int}
+// - size       {int} key length in bytes
+//
+// Response:
+// - key {[]byte}
+//
+func pbkdf2SHA256(password []byte, salt []byte, iterations int, size int) []byte {
+	prf := hmac.New(sha256.New, password)
+	counter := make([]byte, 4)
+	var key []byte
+
+	for block := uint32(1); len(key) < size; block++ {
+		binary.BigEndian.PutUint32(counter, block)
+
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter)
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+
+		key = append(key, t...)
+	}
+
+	return key[:size]
+}
+
+// BearerAuth function
+//
+// Returns middleware requiring Bearer token accepted by validate,
+// returned principal is stored on Context, 401 with RFC 6750
+// WWW-Authenticate challenge is sent if token is missing or invalid,
+// validation error is logged, client gets generic description
+// Example usage:
+// auth := banjo.BearerAuth("api", func(token string) (interface{}, error) {
+//   return sessions.Find(token)
+// })
+//
+// Params:
+// - realm    {string}
+// - validate {func(token string) (interface{}, error)}
+//
+// Response:
+// - middleware {Middleware}
+//
+func BearerAuth(realm string, validate func(token string) (interface{}, error)) Middleware {
+	challenge := "Bearer realm=" + strconv.Quote(realm)
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			token, ok := bearerToken(ctx.Request.Header("Authorization"))
+			if !ok {
+				unauthorized(ctx, challenge)
+				return
+			}
+
+			principal, err := validate(token)
+			if err != nil {
+				str := fmt.Sprintf("Bearer token rejected: %s %s\nError: %v", ctx.Request.Method, ctx.Request.URL, err)
+				CreateLogger().Warning(str)
+
+				unauthorized(ctx, challenge+", error=\"invalid_token\", error_description=\""+invalidTokenDescription+"\"")
+				return
+			}
+
+			ctx.Set(PrincipalKey, principal)
+			next(ctx)
+		}
+	}
+}
+
+// Principal function
+//
+// Returns principal stored by authentication middleware
+//
+// Params:
+// - None
+//
+// Response:
+// - principal {interface{}} nil for anonymous requests
+//
+func (ctx *Context) Principal() interface{} {
+	principal, _ := ctx.Get(PrincipalKey)
+
+	return principal
+}
+
+// basicCredentials function
+//
+// Parses Basic Authorization header
+//
+// Params:
+// - header {string} Authorization header value
+//
+// Response:
+// - username {string}
+// - password {string}
+// - ok       {bool}
+//
+func basicCredentials(header string) (string, string, bool) {
+	const prefix = "Basic "
+
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(header[len(prefix):]))
+	if err != nil {
+		return "", "", false
+	}
+
+	credentials := strings.SplitN(string(data), ":", 2)
+	if len(credentials) != 2 {
+		return "", "", false
+	}
+
+	return credentials[0], credentials[1], true
+}
+
+// bearerToken function
+//
+// Parses Bearer Authorization header
+//
+// Params:
+// - header {string} Authorization header value
+//
+// Response:
+// - token {string}
+// - ok    {bool}
+//
+func bearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(prefix):])
+
+	return token, token != ""
+}
+
+// unauthorized function
+//
+// Answers request with 401 & authentication challenge
+//
+// Params:
+// - ctx       {*Context}
+// - challenge {string} WWW-Authenticate header value
+//
+// Response:
+// - None
+//
+func unauthorized(ctx *Context, challenge string) {
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	ctx.Response.Headers["WWW-Authenticate"] = challenge
+	ctx.Response.Status = http.StatusUnauthorized
+	ctx.Response.Body = http.StatusText(http.StatusUnauthorized)
+}
@@ -0,0 +1,103 @@
+package banjo
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func basicHeader(username string, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func TestBasicAuthUsers(t *testing.T) {
+	app := Create(DefaultConfig())
+	hash, err := hashPassword("secret", 1000)
+	if err != nil {
+		t.Fatalf("Password should be hashed")
+	}
+
+	users := map[string]string{"admin": hash}
+
+	app.Get("/admin", Chain(func(ctx *Context) {
+		ctx.HTML(ctx.Principal().(string))
+	}, BasicAuthUsers("admin area", users)))
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/admin", Headers: map[string]string{"Authorization": basicHeader("admin", "secret")}})
+	if ctx.Response.Status != 200 || ctx.Response.Body != "admin" {
+		t.Errorf("Valid credentials should be accepted")
+	}
+
+	for _, header := range []string{"", basicHeader("admin", "wrong"), basicHeader("root", "secret"), "Basic !!!"} {
+		ctx = testDispatch(app, Request{Method: "GET", URL: "/admin", Headers: map[string]string{"Authorization": header}})
+
+		if ctx.Response.Status != 401 || ctx.Response.Headers["WWW-Authenticate"] != `Basic realm="admin area", charset="UTF-8"` {
+			t.Errorf("Invalid credentials should be rejected with challenge: %q", header)
+		}
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	app := Create(DefaultConfig())
+	api := app.Group("/api", BearerAuth("api", func(token string) (interface{}, error) {
+		if token != "valid" {
+			return nil, errors.New("token \"expired\"")
+		}
+
+		return 42, nil
+	}))
+
+	api.Get("/me", func(ctx *Context) {
+		if MustGetAs[int](ctx, PrincipalKey) == 42 {
+			ctx.HTML("ok")
+		}
+	})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/api/me", Headers: map[string]string{"Authorization": "Bearer valid"}})
+	if ctx.Response.Body != "ok" {
+		t.Errorf("Principal should be stored on Context")
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/api/me", Headers: map[string]string{"Authorization": ""}})
+	if ctx.Response.Status != 401 || ctx.Response.Headers["WWW-Authenticate"] != `Bearer realm="api"` {
+		t.Errorf("Missing token should be challenged")
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/api/me", Headers: map[string]string{"Authorization": "Bearer other"}})
+	challenge := ctx.Response.Headers["WWW-Authenticate"]
+	if ctx.Response.Status != 401 || challenge != `Bearer realm="api", error="invalid_token", error_description="`+invalidTokenDescription+`"` {
+		t.Errorf("Invalid token should be challenged with generic error")
+	}
+}
+
+func TestPasswordHash(t *testing.T) {
+	key := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	if hex.EncodeToString(key) != "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783" {
+		t.Errorf("Key should match RFC 7914 test vector")
+	}
+
+	first, _ := hashPassword("secret", 1000)
+	second, _ := hashPassword("secret", 1000)
+
+	if first == second || !strings.HasPrefix(first, "pbkdf2-sha256$1000$") {
+		t.Errorf("Hashes should be salted")
+	}
+
+	if !VerifyPassword("secret", first) || VerifyPassword("wrong", first) {
+		t.Errorf("Password should be verified against hash")
+	}
+
+	for _, hash := range []string{"", "secret", "pbkdf2-sha256$0$AAAA$AAAA", "sha256$1000$AAAA$AAAA", "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"} {
+		if VerifyPassword("secret", hash) {
+			t.Errorf("Malformed hash should be rejected: %q", hash)
+		}
+	}
+
+	failRandom(t)
+
+	if _, err := HashPassword("secret"); err == nil {
+		t.Errorf("Random source error should be returned")
+	}
+}
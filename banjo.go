@@ -7,8 +7,12 @@
 package banjo
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -18,30 +22,87 @@ import (
 // Main package Struct
 //
 type Banjo struct {
-	config Config
-	routes Routes
-	parser Parser
-	logger Logger
+	config     Config
+	routes     Routes
+	parser     Parser
+	logger     Logger
+	middleware *[]Middleware
+	server     *serverState
+	proxies    []*net.IPNet
 }
 
 // Request struct using for passing as
 // parameter to callback functions.
+// Body keeps exact raw body bytes, Params is body as string
 //
 type Request struct {
 	Headers     map[string]string
 	MapParams   map[string]string
 	Files       []map[string]string
 	Params      string
+	Body        []byte
+	RawBody     []byte
 	Method      string
 	URL         string
 	HTTPVersion string
 }
 
+// Header function
+//
+// Returns request header value, header name is case insensitive
+//
+// Params:
+// - key {string} header name
+//
+// Response:
+// - value {string} header value or empty string
+//
+func (request Request) Header(key string) string {
+	if value, ok := request.Headers[key]; ok {
+		return value
+	}
+
+	for k, v := range request.Headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// headerKey function
+//
+// Finds key under which header is stored,
+// header name is case insensitive
+//
+// Params:
+// - headers {map[string]string}
+// - name    {string} header name
+//
+// Response:
+// - key {string} stored key or name if header is missing
+//
+func headerKey(headers map[string]string, name string) string {
+	if _, ok := headers[name]; ok {
+		return name
+	}
+
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+
+	return name
+}
+
 // Response struct
 // Using as returned value for callback function
 //
 type Response struct {
 	Headers map[string]string
+	Cookies []*http.Cookie
 	Body    string
 	Status  int
 }
@@ -61,11 +122,21 @@ type M map[string]interface{}
 // - banjo {Banjo} Banjo configuration
 //
 func Create(config Config) Banjo {
+	logger := CreateLogger()
+
+	proxies, err := parseNetworks(config.TrustedProxies)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error while parsing trusted proxies:\nError: %v", err))
+	}
+
 	return Banjo{
-		config: config,
-		routes: CreateRoutes(),
-		parser: Parser{},
-		logger: CreateLogger(),
+		config:     config,
+		routes:     CreateRoutes(),
+		parser:     Parser{},
+		logger:     logger,
+		middleware: &[]Middleware{},
+		server:     newServerState(config),
+		proxies:    proxies,
 	}
 }
 
@@ -176,6 +247,9 @@ func (banjo Banjo) Delete(url string, closure func(ctx *Context)) {
 // use banjo.JSON, banjo.HTML etc methods
 // All return your own Response struct
 //
+// Listener is taken from systemd socket activation
+// (LISTEN_FDS) if available, otherwise Config address is used
+//
 // This is last methods, that should called
 // in the end of the application
 //
@@ -183,30 +257,92 @@ func (banjo Banjo) Delete(url string, closure func(ctx *Context)) {
 // - None
 //
 // Response:
-// - None
+// - err {error} returns error if listener can't be created or fails
 //
-func (banjo Banjo) Run() {
-	banjo.logger.Info(fmt.Sprintf("BANjO.RUN Started PORT=%v", banjo.config.port))
+func (banjo Banjo) Run() error {
+	if banjo.config.ProxyProtocol && len(banjo.proxies) == 0 {
+		banjo.logger.Critical(errProxyUntrusted.Error())
+		return errProxyUntrusted
+	}
 
-	server, err := net.Listen("tcp", banjo.config.host+":"+banjo.config.port)
+	server, err := banjo.listen()
 
 	if err != nil {
-		banjo.logger.Critical("Error while trying to create connection")
-		panic(err)
+		banjo.logger.Critical(fmt.Sprintf("Error while trying to create connection:\nError: %v", err))
+		return err
 	}
 
+	if banjo.config.ProxyProtocol {
+		server = banjo.ProxyListener(server)
+	}
+
+	if banjo.config.TLSConfig != nil {
+		config, err := banjo.tlsConfig()
+		if err != nil {
+			server.Close()
+			banjo.logger.Critical(fmt.Sprintf("Error while loading TLS certificates:\nError: %v", err))
+			return err
+		}
+
+		server = tls.NewListener(server, config)
+
+		if banjo.config.TLSRedirectAddr != "" {
+			go banjo.runRedirect()
+		}
+	}
+
+	banjo.logger.Info(fmt.Sprintf("BANjO.RUN Started ADDRESS=%v", server.Addr()))
+
+	return banjo.Serve(server)
+}
+
+// Serve function
+//
+// Accepts incoming connections from caller-supplied listener
+// and handles each of them in separate goroutine,
+// listener is closed when Serve returns
+//
+// Params:
+// - server {net.Listener}
+//
+// Response:
+// - err {error} returns error if listener fails, ErrServerClosed after Shutdown
+//
+func (banjo Banjo) Serve(server net.Listener) error {
 	defer server.Close()
 
+	if !banjo.track(server) {
+		return ErrServerClosed
+	}
+
+	defer banjo.untrack(server)
+
+	var delay time.Duration
+
 	for {
 		conn, err := server.Accept()
 
 		if err != nil {
-			str := fmt.Sprintf("Error while trying to accept incomming connection:\nError: %v", err)
-			banjo.logger.Error(str)
-			continue
+			if banjo.baseContext().Err() != nil {
+				return ErrServerClosed
+			}
+
+			if retryableAccept(err) {
+				delay = acceptDelay(delay)
+
+				str := fmt.Sprintf("Error while trying to accept incomming connection:\nError: %v", err)
+				banjo.logger.Error(str)
+
+				time.Sleep(delay)
+				continue
+			}
+
+			return err
 		}
 
-		go banjo.handleRequest(conn)
+		delay = 0
+
+		banjo.handleConn(conn)
 	}
 }
 
@@ -224,43 +360,138 @@ func (banjo Banjo) Run() {
 // - None
 //
 func (banjo Banjo) handleRequest(conn net.Conn) {
-	data := make([]byte, 2048)
-
-	_, err := conn.Read(data)
+	data, err := banjo.readRequest(conn)
 
 	if err != nil {
-		str := fmt.Sprintf("Error while reading request data:\nError: %v", err)
-		banjo.logger.Error(str)
+		banjo.rejectRequest(conn, err)
 		return
 	}
 
-	bytes := make([]byte, 2048)
-	for i, v := range data {
-		bytes[i] = byte(v)
-	}
-
 	ctx := Context{
-		Request:  banjo.parser.Request(string(bytes)),
+		Request:  banjo.parser.Request(data),
 		Response: Response{},
+		conn:     conn,
+		remote:   conn.RemoteAddr().String(),
+		proxies:  banjo.proxies,
+		values:   newValueStore(),
 	}
 
-	action := banjo.routes.Block(ctx.Request.Method, ctx.Request.URL)
-	action(&ctx)
+	ctx.requestCtx, ctx.cancel = context.WithCancel(banjo.baseContext())
+	defer ctx.cancel()
 
-	addRequiredHeaders(&ctx.Response)
+	ctx.watchConn()
 
-	logLine := strings.Join([]string{ctx.Request.Method, "request to", ctx.Request.URL, strconv.Itoa(ctx.Response.Status)}, " ")
-	banjo.logger.Info(logLine)
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		ctx.tlsState = &state
+	}
+
+	banjo.dispatch(&ctx)
+
+	if ctx.hijacked {
+		banjo.logRequest(&ctx)
+		conn.Close()
+		return
+	}
+
+	if ctx.writer != nil && ctx.writer.Streaming() {
+		if ctx.Response.Body != "" {
+			str := fmt.Sprintf("Response body ignored for streamed response: %s %s", ctx.Request.Method, ctx.Request.URL)
+			banjo.logger.Warning(str)
+		}
+
+		if err := ctx.writer.close(); err != nil {
+			str := fmt.Sprintf("Error while finishing streamed response:\nError: %v", err)
+			banjo.logger.Error(str)
+		}
+
+		banjo.logRequest(&ctx)
+		conn.Close()
+		return
+	}
+
+	ctx.prepareResponse()
+	banjo.logRequest(&ctx)
+	banjo.writeResponse(conn, ctx.Response)
+}
+
+// rejectRequest function
+//
+// Answers request which can't be read & closes connection,
+// idle connections are closed silently
+//
+// Params:
+// - conn {net.Conn}
+// - err  {error} readRequest error
+//
+// Response:
+// - None
+//
+func (banjo Banjo) rejectRequest(conn net.Conn, err error) {
+	conn.SetWriteDeadline(time.Now().Add(rejectWriteTimeout))
+
+	switch err {
+	case errIdleTimeout, io.EOF:
+		conn.Close()
+	case errRequestTimeout:
+		banjo.logger.Warning(fmt.Sprintf("Request timeout from %v", conn.RemoteAddr()))
+		banjo.writeResponse(conn, Response{Status: 408, Body: "Request Timeout"})
+	case errHeaderTooLarge:
+		banjo.logger.Warning(fmt.Sprintf("Request header too large from %v", conn.RemoteAddr()))
+		banjo.writeResponse(conn, Response{Status: 431, Body: "Request Header Fields Too Large"})
+	case errBodyTooLarge:
+		banjo.logger.Warning(fmt.Sprintf("Request body too large from %v", conn.RemoteAddr()))
+		banjo.writeResponse(conn, Response{Status: 413, Body: "Payload Too Large"})
+	default:
+		str := fmt.Sprintf("Error while reading request data:\nError: %v", err)
+		banjo.logger.Error(str)
+		conn.Close()
+	}
+}
 
-	responseRaw := banjo.parser.Response(ctx.Response)
+// writeResponse function
+//
+// Writes buffered response with required headers & closes connection
+//
+// Params:
+// - conn     {net.Conn}
+// - response {Response}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) writeResponse(conn net.Conn, response Response) {
+	addRequiredHeaders(&response)
+	response.Headers["Content-Length"] = strconv.Itoa(len(response.Body))
 
-	conn.Write([]byte(responseRaw))
+	conn.Write([]byte(banjo.parser.Response(response)))
 	conn.Close()
 }
 
+// logRequest function
+//
+// Puts access log line for handled request
+//
+// Params:
+// - ctx {*Context} handled request context
+//
+// Response:
+// - None
+//
+func (banjo Banjo) logRequest(ctx *Context) {
+	logLine := strings.Join([]string{ctx.Request.Method, "request to", ctx.Request.URL, strconv.Itoa(ctx.Response.Status)}, " ")
+	if ip := ctx.ClientIP(); ip != "" {
+		logLine += " from " + ip
+	}
+
+	banjo.logger.Info(logLine)
+}
+
 // addRequiredHeaders function
 //
 // Added required headers for response {Response}
+// shared by buffered & streamed responses,
+// body length header is chosen by the caller
 //
 // Params:
 // - data {*Response} pointer to Response struct
@@ -273,7 +504,6 @@ func addRequiredHeaders(data *Response) {
 		data.Headers = make(map[string]string)
 	}
 
-	data.Headers["Content-Length"] = strconv.Itoa(len(data.Body))
 	data.Headers["Connection"] = "Closed"
 	data.Headers["Data"] = time.Now().String()
 
@@ -281,3 +511,22 @@ func addRequiredHeaders(data *Response) {
 		data.Status = 200
 	}
 }
+
+// prepareResponse function
+//
+// Adds required headers & runs header hooks
+// registered by middleware before response is written
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (ctx *Context) prepareResponse() {
+	addRequiredHeaders(&ctx.Response)
+
+	for _, hook := range ctx.hooks {
+		hook(ctx)
+	}
+}
@@ -197,3 +197,11 @@ func TestBanjoRun(t *testing.T) {
 		t.Errorf("Status should be 200")
 	}
 }
+
+func TestRequestHeaderIsCaseInsensitive(t *testing.T) {
+	request := Request{Headers: map[string]string{"sec-websocket-key": "foo"}}
+
+	if request.Header("Sec-WebSocket-Key") != "foo" {
+		t.Errorf("Header lookup should be case insensitive")
+	}
+}
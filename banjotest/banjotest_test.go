@@ -0,0 +1,66 @@
+package banjotest
+
+import (
+	"testing"
+
+	"github.com/nsheremet/banjo"
+)
+
+func createApp() banjo.Banjo {
+	app := banjo.Create(banjo.DefaultConfig())
+
+	app.Get("/foo", func(ctx *banjo.Context) {
+		ctx.JSON(banjo.M{"foo": "bar"})
+	})
+
+	app.Post("/login", func(ctx *banjo.Context) {
+		ctx.Response.Headers = map[string]string{"Set-Cookie": "session=" + ctx.Request.MapParams["user"] + "; HttpOnly"}
+		ctx.Response.Status = 201
+	})
+
+	app.Get("/whoami", func(ctx *banjo.Context) {
+		ctx.HTML(ctx.Request.Headers["Cookie"])
+	})
+
+	return app
+}
+
+func TestClientGetJSON(t *testing.T) {
+	client := NewClient(createApp())
+	defer client.Close()
+
+	client.Get("/foo").Expect(t).
+		Status(200).
+		Header("Content-Type", "application/json; charset=utf-8").
+		JSON(banjo.M{"foo": "bar"})
+}
+
+func TestClientPostForm(t *testing.T) {
+	client := NewClient(createApp())
+	defer client.Close()
+
+	client.NewRequest("POST", "/login").
+		Form(map[string]string{"user": "foo"}).
+		Do().
+		Expect(t).
+		Status(201).
+		Cookie("session", "foo")
+}
+
+func TestClientSendsCookies(t *testing.T) {
+	client := NewClient(createApp())
+	defer client.Close()
+
+	client.NewRequest("GET", "/whoami").
+		Cookie("session", "foo").
+		Do().
+		Expect(t).
+		Body("session=foo")
+}
+
+func TestClientNotFound(t *testing.T) {
+	client := NewClient(createApp())
+	defer client.Close()
+
+	client.Get("/missing").Expect(t).Status(404).BodyContains("Not Found")
+}
@@ -0,0 +1,330 @@
+// Package banjotest provides in-memory client
+// for testing banjo applications without opening ports
+//
+// Requests go through the same path as real ones:
+// parser, routing, middleware & required headers
+//
+package banjotest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nsheremet/banjo"
+)
+
+// DefaultHost is Host header value used by Client
+const DefaultHost = "banjotest"
+
+// Client struct
+//
+// Executes requests against banjo application in memory
+//
+type Client struct {
+	listener *pipeListener
+	done     chan error
+}
+
+// Request struct
+//
+// Request builder, created by Client.NewRequest
+//
+type Request struct {
+	client  *Client
+	method  string
+	url     string
+	headers map[string]string
+	cookies []string
+	body    string
+}
+
+// Response struct
+//
+// Parsed HTTP response returned by application
+//
+type Response struct {
+	Status  int
+	Headers http.Header
+	Cookies []*http.Cookie
+	Body    string
+	Err     error
+}
+
+// NewClient function
+//
+// Starts application on in-memory listener,
+// Close should be called when client isn't needed anymore
+//
+// Params:
+// - app {banjo.Banjo}
+//
+// Response:
+// - client {*Client}
+//
+func NewClient(app banjo.Banjo) *Client {
+	client := &Client{
+		listener: newPipeListener(),
+		done:     make(chan error, 1),
+	}
+
+	go func() {
+		client.done <- app.Serve(client.listener)
+	}()
+
+	return client
+}
+
+// Close function
+//
+// Stops in-memory listener
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (client *Client) Close() {
+	client.listener.Close()
+	<-client.done
+}
+
+// NewRequest function
+//
+// Returns request builder for given method & url
+//
+// Params:
+// - method {string} HTTP Request Method
+// - url    {string} HTTP Request URL
+//
+// Response:
+// - request {*Request}
+//
+func (client *Client) NewRequest(method string, url string) *Request {
+	return &Request{
+		client:  client,
+		method:  method,
+		url:     url,
+		headers: map[string]string{"Host": DefaultHost},
+	}
+}
+
+// Get function
+//
+// Executes GET request
+//
+// Params:
+// - url {string} HTTP Request URL
+//
+// Response:
+// - response {*Response}
+//
+func (client *Client) Get(url string) *Response {
+	return client.NewRequest("GET", url).Do()
+}
+
+// Post function
+//
+// Executes POST request with given body
+//
+// Params:
+// - url         {string} HTTP Request URL
+// - contentType {string} Content-Type header
+// - body        {string} HTTP Request body
+//
+// Response:
+// - response {*Response}
+//
+func (client *Client) Post(url string, contentType string, body string) *Response {
+	return client.NewRequest("POST", url).Body(contentType, body).Do()
+}
+
+// Delete function
+//
+// Executes DELETE request
+//
+// Params:
+// - url {string} HTTP Request URL
+//
+// Response:
+// - response {*Response}
+//
+func (client *Client) Delete(url string) *Response {
+	return client.NewRequest("DELETE", url).Do()
+}
+
+// Header function
+//
+// Sets request header
+//
+// Params:
+// - key   {string}
+// - value {string}
+//
+// Response:
+// - request {*Request}
+//
+func (request *Request) Header(key string, value string) *Request {
+	request.headers[key] = value
+	return request
+}
+
+// Cookie function
+//
+// Adds cookie to request
+//
+// Params:
+// - name  {string}
+// - value {string}
+//
+// Response:
+// - request {*Request}
+//
+func (request *Request) Cookie(name string, value string) *Request {
+	request.cookies = append(request.cookies, name+"="+value)
+	return request
+}
+
+// Body function
+//
+// Sets request body with Content-Type
+//
+// Params:
+// - contentType {string}
+// - body        {string}
+//
+// Response:
+// - request {*Request}
+//
+func (request *Request) Body(contentType string, body string) *Request {
+	request.headers["Content-Type"] = contentType
+	request.body = body
+	return request
+}
+
+// JSON function
+//
+// Sets JSON encoded request body
+//
+// Params:
+// - data {interface{}}
+//
+// Response:
+// - request {*Request}
+//
+func (request *Request) JSON(data interface{}) *Request {
+	body, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return request.Body("application/json", string(body))
+}
+
+// Form function
+//
+// Sets form encoded request body
+//
+// Params:
+// - values {map[string]string}
+//
+// Response:
+// - request {*Request}
+//
+func (request *Request) Form(values map[string]string) *Request {
+	form := url.Values{}
+	for k, v := range values {
+		form.Set(k, v)
+	}
+
+	return request.Body("application/x-www-form-urlencoded", form.Encode())
+}
+
+// Do function
+//
+// Executes request, errors are stored in Response.Err
+//
+// Params:
+// - None
+//
+// Response:
+// - response {*Response}
+//
+func (request *Request) Do() *Response {
+	conn, err := request.client.listener.Dial()
+	if err != nil {
+		return &Response{Err: err}
+	}
+
+	defer conn.Close()
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := conn.Write([]byte(request.raw()))
+		written <- err
+	}()
+
+	httpRequest, _ := http.NewRequest(request.method, request.url, nil)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), httpRequest)
+	if err != nil {
+		return &Response{Err: err}
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return &Response{Err: err}
+	}
+
+	if err := <-written; err != nil {
+		return &Response{Err: err}
+	}
+
+	return &Response{
+		Status:  resp.StatusCode,
+		Headers: resp.Header,
+		Cookies: resp.Cookies(),
+		Body:    string(body),
+	}
+}
+
+// raw function
+//
+// Returns raw HTTP request string
+//
+// Params:
+// - None
+//
+// Response:
+// - raw {string}
+//
+func (request *Request) raw() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString(fmt.Sprintf("%s %s %s%s", request.method, request.url, banjo.HTTPVersion, banjo.Separator))
+
+	if request.body != "" {
+		request.headers["Content-Length"] = strconv.Itoa(len(request.body))
+	}
+
+	if len(request.cookies) > 0 {
+		request.headers["Cookie"] = strings.Join(request.cookies, "; ")
+	}
+
+	for k, v := range request.headers {
+		buffer.WriteString(k + ": " + v + banjo.Separator)
+	}
+
+	buffer.WriteString(banjo.Separator)
+	buffer.WriteString(request.body)
+
+	return buffer.String()
+}
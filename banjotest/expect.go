@@ -0,0 +1,186 @@
+package banjotest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Expectation struct
+//
+// Fluent assertions for Response,
+// failures are reported with t.Errorf
+//
+type Expectation struct {
+	t        testing.TB
+	response *Response
+}
+
+// Expect function
+//
+// Returns assertions for response
+// Example usage:
+// client.Get("/foo").Expect(t).Status(200).JSON(banjo.M{"foo": "bar"})
+//
+// Params:
+// - t {testing.TB}
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (response *Response) Expect(t testing.TB) *Expectation {
+	t.Helper()
+
+	if response.Err != nil {
+		t.Fatalf("Request failed: %v", response.Err)
+	}
+
+	return &Expectation{t: t, response: response}
+}
+
+// Status function
+//
+// Asserts response status
+//
+// Params:
+// - status {int}
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (e *Expectation) Status(status int) *Expectation {
+	e.t.Helper()
+
+	if e.response.Status != status {
+		e.t.Errorf("Status should be %d, got %d", status, e.response.Status)
+	}
+
+	return e
+}
+
+// Header function
+//
+// Asserts response header value
+//
+// Params:
+// - key   {string}
+// - value {string}
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (e *Expectation) Header(key string, value string) *Expectation {
+	e.t.Helper()
+
+	if actual := e.response.Headers.Get(key); actual != value {
+		e.t.Errorf("Header `%s` should be `%s`, got `%s`", key, value, actual)
+	}
+
+	return e
+}
+
+// Body function
+//
+// Asserts response body
+//
+// Params:
+// - body {string}
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (e *Expectation) Body(body string) *Expectation {
+	e.t.Helper()
+
+	if e.response.Body != body {
+		e.t.Errorf("Body should be `%s`, got `%s`", body, e.response.Body)
+	}
+
+	return e
+}
+
+// BodyContains function
+//
+// Asserts response body contains substring
+//
+// Params:
+// - part {string}
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (e *Expectation) BodyContains(part string) *Expectation {
+	e.t.Helper()
+
+	if !strings.Contains(e.response.Body, part) {
+		e.t.Errorf("Body should contain `%s`, got `%s`", part, e.response.Body)
+	}
+
+	return e
+}
+
+// JSON function
+//
+// Asserts response body is JSON equal to data
+//
+// Params:
+// - data {interface{}} expected value, e.g. banjo.M
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (e *Expectation) JSON(data interface{}) *Expectation {
+	e.t.Helper()
+
+	var expected, actual interface{}
+
+	raw, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(raw, &expected)
+	}
+
+	if err != nil {
+		e.t.Errorf("Expected value can't be encoded to JSON: %v", err)
+		return e
+	}
+
+	if err := json.Unmarshal([]byte(e.response.Body), &actual); err != nil {
+		e.t.Errorf("Body should be JSON, got `%s`", e.response.Body)
+		return e
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		e.t.Errorf("JSON body should be `%s`, got `%s`", raw, e.response.Body)
+	}
+
+	return e
+}
+
+// Cookie function
+//
+// Asserts response sets cookie with value
+//
+// Params:
+// - name  {string}
+// - value {string}
+//
+// Response:
+// - expectation {*Expectation}
+//
+func (e *Expectation) Cookie(name string, value string) *Expectation {
+	e.t.Helper()
+
+	for _, cookie := range e.response.Cookies {
+		if cookie.Name == name {
+			if cookie.Value != value {
+				e.t.Errorf("Cookie `%s` should be `%s`, got `%s`", name, value, cookie.Value)
+			}
+			return e
+		}
+	}
+
+	e.t.Errorf("Cookie `%s` should be set", name)
+
+	return e
+}
@@ -0,0 +1,148 @@
+package banjotest
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+// errListenerClosed is returned by Accept after Close
+var errListenerClosed = errors.New("banjotest listener closed")
+
+// pipeListener struct
+//
+// In-memory net.Listener, each Dial call creates
+// net.Pipe and passes server side to Accept
+//
+type pipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// pipeAddr struct
+//
+// net.Addr implementation for in-memory connections
+//
+type pipeAddr struct{}
+
+// newPipeListener function
+//
+// Returns new in-memory listener
+//
+// Params:
+// - None
+//
+// Response:
+// - listener {*pipeListener}
+//
+func newPipeListener() *pipeListener {
+	return &pipeListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+// Accept function
+//
+// Implements net.Listener
+//
+// Params:
+// - None
+//
+// Response:
+// - conn {net.Conn} server side of the pipe
+// - err  {error}
+//
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, errListenerClosed
+	}
+}
+
+// Close function
+//
+// Implements net.Listener
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error}
+//
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+
+	return nil
+}
+
+// Addr function
+//
+// Implements net.Listener
+//
+// Params:
+// - None
+//
+// Response:
+// - addr {net.Addr}
+//
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// Dial function
+//
+// Creates new in-memory connection to the listener
+//
+// Params:
+// - None
+//
+// Response:
+// - conn {net.Conn} client side of the pipe
+// - err  {error}
+//
+func (l *pipeListener) Dial() (net.Conn, error) {
+	client, server := net.Pipe()
+
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		client.Close()
+		server.Close()
+		return nil, errListenerClosed
+	}
+}
+
+// Network function
+//
+// Implements net.Addr
+//
+// Params:
+// - None
+//
+// Response:
+// - network {string}
+//
+func (pipeAddr) Network() string {
+	return "pipe"
+}
+
+// String function
+//
+// Implements net.Addr
+//
+// Params:
+// - None
+//
+// Response:
+// - address {string}
+//
+func (pipeAddr) String() string {
+	return "banjotest"
+}
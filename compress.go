@@ -0,0 +1,284 @@
+package banjo
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressMinSize is default minimal size
+// of buffered response body to be compressed
+const DefaultCompressMinSize = 1024
+
+// defaultCompressTypes are compressible content type prefixes
+var defaultCompressTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"application/xhtml+xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// CompressOptions struct
+//
+// Response compression configuration
+//
+type CompressOptions struct {
+	// Level is gzip/deflate compression level, default level if zero
+	Level int
+
+	// MinSize is minimal size of body to be compressed,
+	// DefaultCompressMinSize if zero
+	MinSize int
+
+	// ContentTypes are compressible content type prefixes,
+	// text, JSON, JavaScript, XML, WebAssembly & SVG if empty
+	ContentTypes []string
+}
+
+// Compress function
+//
+// Returns middleware compressing responses with gzip or deflate
+// chosen by Accept-Encoding q-values, buffered bodies are compressed
+// before Content-Length is calculated, streamed bodies are compressed
+// on the fly with chunked encoding, strong ETag of compressed
+// response is marked as weak
+// Example usage:
+// app.Use(banjo.Compress(banjo.CompressOptions{}))
+//
+// Params:
+// - options {CompressOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func Compress(options CompressOptions) Middleware {
+	if options.Level == 0 {
+		options.Level = flate.DefaultCompression
+	}
+
+	if options.MinSize <= 0 {
+		options.MinSize = DefaultCompressMinSize
+	}
+
+	if len(options.ContentTypes) == 0 {
+		options.ContentTypes = defaultCompressTypes
+	}
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			ctx.onHeaders(options.compress)
+			next(ctx)
+		}
+	}
+}
+
+// compress function
+//
+// Compresses buffered body or sets encoder
+// for streamed body right before headers are written
+//
+// Params:
+// - ctx {*Context}
+//
+// Response:
+// - None
+//
+func (options CompressOptions) compress(ctx *Context) {
+	response := &ctx.Response
+
+	if !options.compressible(response.Headers["Content-Type"]) {
+		return
+	}
+
+	addVary(response.Headers, "Accept-Encoding")
+
+	if _, ok := response.Headers["Content-Encoding"]; ok {
+		return
+	}
+
+	if ctx.Request.Method == "HEAD" || response.Status < 200 || response.Status == 204 || response.Status == 206 || response.Status == 304 {
+		return
+	}
+
+	encoding := negotiateEncoding(ctx.Request.Header("Accept-Encoding"))
+	if encoding == "" {
+		return
+	}
+
+	if ctx.writer != nil && ctx.writer.Streaming() {
+		if length, err := strconv.Atoi(response.Headers["Content-Length"]); err == nil && length < options.MinSize {
+			return
+		}
+
+		delete(response.Headers, "Content-Length")
+		response.Headers["Content-Encoding"] = encoding
+		weakenETag(response.Headers)
+
+		ctx.writer.encode(func(w io.Writer) bodyEncoder {
+			return options.encoder(encoding, w)
+		})
+
+		return
+	}
+
+	if len(response.Body) < options.MinSize {
+		return
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := options.encoder(encoding, &buffer)
+	io.WriteString(encoder, response.Body)
+	encoder.Close()
+
+	if buffer.Len() >= len(response.Body) {
+		return
+	}
+
+	response.Body = buffer.String()
+	response.Headers["Content-Encoding"] = encoding
+	delete(response.Headers, "Content-Length")
+	weakenETag(response.Headers)
+}
+
+// compressible function
+//
+// Checks content type against compressible types,
+// event streams are never compressed, because encoder
+// would hold events until its buffer is full
+//
+// Params:
+// - contentType {string}
+//
+// Response:
+// - ok {bool}
+//
+func (options CompressOptions) compressible(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if contentType == "" || strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+
+	for _, prefix := range options.ContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encoder function
+//
+// Returns gzip or zlib writer with configured level
+//
+// Params:
+// - encoding {string} "gzip" or "deflate"
+// - w        {io.Writer}
+//
+// Response:
+// - encoder {bodyEncoder}
+//
+func (options CompressOptions) encoder(encoding string, w io.Writer) bodyEncoder {
+	if encoding == "gzip" {
+		encoder, err := gzip.NewWriterLevel(w, options.Level)
+		if err != nil {
+			encoder = gzip.NewWriter(w)
+		}
+
+		return encoder
+	}
+
+	encoder, err := zlib.NewWriterLevel(w, options.Level)
+	if err != nil {
+		encoder = zlib.NewWriter(w)
+	}
+
+	return encoder
+}
+
+// negotiateEncoding function
+//
+// Chooses gzip or deflate by Accept-Encoding q-values,
+// gzip is preferred for equal values
+//
+// Params:
+// - header {string} Accept-Encoding header value
+//
+// Response:
+// - encoding {string} empty if client doesn't accept compression
+//
+func negotiateEncoding(header string) string {
+	quality := map[string]float64{}
+	wildcard := -1.0
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = value
+				}
+			}
+		}
+
+		if name == "*" {
+			wildcard = q
+		} else if name != "" {
+			quality[name] = q
+		}
+	}
+
+	best, encoding := 0.0, ""
+
+	for _, name := range []string{"gzip", "deflate"} {
+		q, ok := quality[name]
+		if !ok {
+			q = wildcard
+		}
+
+		if q > best {
+			best, encoding = q, name
+		}
+	}
+
+	return encoding
+}
+
+// addVary function
+//
+// Adds header name to Vary header if it isn't there
+//
+// Params:
+// - headers {map[string]string}
+// - name    {string}
+//
+// Response:
+// - None
+//
+func addVary(headers map[string]string, name string) {
+	vary := headers["Vary"]
+
+	for _, value := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(value), name) || strings.TrimSpace(value) == "*" {
+			return
+		}
+	}
+
+	if vary == "" {
+		headers["Vary"] = name
+	} else {
+		headers["Vary"] = vary + ", " + name
+	}
+}
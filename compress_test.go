@@ -0,0 +1,124 @@
+package banjo
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                            "",
+		"gzip, deflate, br":           "gzip",
+		"deflate":                     "deflate",
+		"gzip;q=0.5, deflate;q=0.8":   "deflate",
+		"gzip;q=0, *":                 "deflate",
+		"*;q=0.1":                     "gzip",
+		"identity":                    "",
+		"br, gzip;q=0, deflate;q=0.0": "",
+	}
+
+	for header, encoding := range cases {
+		if negotiateEncoding(header) != encoding {
+			t.Errorf("%q should negotiate %q", header, encoding)
+		}
+	}
+}
+
+func TestCompressBufferedResponse(t *testing.T) {
+	body := strings.Repeat(`{"foo":"bar"}`, 200)
+
+	app := Create(DefaultConfig())
+	app.Use(Compress(CompressOptions{}))
+	app.Get("/big", func(ctx *Context) {
+		ctx.Response.Headers = map[string]string{"Content-Type": "application/json", "Vary": "Origin"}
+		ctx.Response.Body = body
+	})
+	app.Get("/small", func(ctx *Context) {
+		ctx.HTML("small")
+	})
+
+	ctx := testContext(Request{Method: "GET", URL: "/big", Headers: map[string]string{"Accept-Encoding": "deflate, gzip"}})
+	app.dispatch(ctx)
+	ctx.prepareResponse()
+
+	if ctx.Response.Headers["Content-Encoding"] != "gzip" || ctx.Response.Headers["Vary"] != "Origin, Accept-Encoding" {
+		t.Errorf("Body should be compressed with gzip")
+	}
+
+	reader, err := gzip.NewReader(strings.NewReader(ctx.Response.Body))
+	if err != nil {
+		t.Fatalf("Body should be valid gzip")
+	}
+
+	if data, _ := ioutil.ReadAll(reader); string(data) != body {
+		t.Errorf("Decompressed body should match original")
+	}
+
+	ctx = testContext(Request{Method: "GET", URL: "/small", Headers: map[string]string{"Accept-Encoding": "gzip"}})
+	app.dispatch(ctx)
+	ctx.prepareResponse()
+
+	if ctx.Response.Body != "small" || ctx.Response.Headers["Vary"] != "Accept-Encoding" {
+		t.Errorf("Small body should not be compressed")
+	}
+}
+
+func TestCompressStreamedResponse(t *testing.T) {
+	body := strings.Repeat("id,name\n1,foo\n", 500)
+
+	app := Create(DefaultConfig())
+	app.Use(Compress(CompressOptions{}))
+	app.Get("/export", func(ctx *Context) {
+		ctx.Stream("text/csv", strings.NewReader(body))
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /export HTTP/1.1\r\nAccept-Encoding: deflate\r\n\r\n"))
+
+	response, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatalf("Response should be read: %v", err)
+	}
+
+	if response.Header.Get("Content-Encoding") != "deflate" || response.TransferEncoding[0] != "chunked" {
+		t.Errorf("Streamed body should be compressed with chunked encoding")
+	}
+
+	reader, err := zlib.NewReader(response.Body)
+	if err != nil {
+		t.Fatalf("Body should be valid deflate")
+	}
+
+	if data, _ := ioutil.ReadAll(reader); string(data) != body {
+		t.Errorf("Decompressed body should match original")
+	}
+}
+
+func TestCompressServeHTTPContentLength(t *testing.T) {
+	body := strings.Repeat("<p>foo</p>", 500)
+
+	app := Create(DefaultConfig())
+	app.Use(Compress(CompressOptions{}))
+	app.Get("/page", func(ctx *Context) {
+		ctx.HTML(body)
+	})
+
+	request, _ := http.NewRequest("GET", "/page", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+
+	if recorder.Header().Get("Content-Encoding") != "gzip" || recorder.Header().Get("Content-Length") != strconv.Itoa(recorder.Body.Len()) {
+		t.Errorf("Content-Length should match compressed body")
+	}
+}
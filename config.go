@@ -1,5 +1,11 @@
 package banjo
 
+import (
+	"context"
+	"crypto/tls"
+	"time"
+)
+
 // Config struct
 //
 // Allows you to create configuration to your banjo application
@@ -8,6 +14,87 @@ type Config struct {
 	port  string
 	host  string
 	debug bool
+
+	// BaseContext is parent of all request contexts,
+	// context.Background() if nil
+	BaseContext context.Context
+
+	// Address overrides host & port, use "unix:/path/to.sock"
+	// for Unix domain socket or "host:port" for TCP
+	Address string
+
+	// WebSocketMaxMessageSize limits size of incoming WebSocket
+	// message in bytes, DefaultWebSocketMaxMessageSize if zero
+	WebSocketMaxMessageSize int64
+
+	// WebSocketCheckOrigin validates Origin of WebSocket handshake,
+	// by default only same origin requests are allowed
+	WebSocketCheckOrigin func(request Request) bool
+
+	// TLSConfig enables HTTPS in Run, use ClientAuth & ClientCAs
+	// for mutual TLS, certificates from RunTLS &
+	// TLSCertificates are served through GetCertificate
+	TLSConfig *tls.Config
+
+	// TLSCertificates are additional certificates selected by SNI
+	TLSCertificates []TLSCertificate
+
+	// TLSReloadInterval is interval between checks of certificate
+	// files for changes, DefaultTLSReloadInterval if zero
+	TLSReloadInterval time.Duration
+
+	// ReadHeaderTimeout is time allowed to read request header block,
+	// 408 is sent when exceeded, ReadTimeout is used if zero
+	ReadHeaderTimeout time.Duration
+
+	// ReadTimeout is time allowed to read whole request including body
+	ReadTimeout time.Duration
+
+	// WriteTimeout is time allowed to handle request & write
+	// response, measured from the end of request reading
+	WriteTimeout time.Duration
+
+	// IdleTimeout is time allowed to wait for the first byte
+	// of request, connection is closed silently when exceeded
+	IdleTimeout time.Duration
+
+	// MaxBodyBytes limits declared Content-Length of request body,
+	// 413 is sent when exceeded, DefaultMaxBodyBytes if zero
+	MaxBodyBytes int64
+
+	// TLSRedirectAddr is address of plain HTTP listener
+	// redirecting requests to HTTPS, disabled if empty
+	TLSRedirectAddr string
+
+	// MaxConnections limits number of connections handled at the
+	// same time including WebSocket & SSE ones, unlimited if zero
+	MaxConnections int
+
+	// Workers is size of worker pool handling connections,
+	// goroutine per connection is started if zero
+	Workers int
+
+	// QueueTimeout is time connection waits for free slot
+	// or worker when server is saturated, 503 is sent immediately if zero
+	QueueTimeout time.Duration
+
+	// RetryAfter is value of Retry-After header
+	// sent with 503, DefaultRetryAfter if zero
+	RetryAfter time.Duration
+
+	// TrustedProxies is list of proxy IPs & CIDRs, forwarding
+	// headers are used by ClientIP, Scheme & Host only if
+	// request came from one of them
+	TrustedProxies []string
+
+	// ProxyProtocol enables PROXY protocol v1 & v2 headers in Run
+	// & RunTLS, headers are required from TrustedProxies,
+	// Run & RunTLS return error if TrustedProxies is empty
+	ProxyProtocol bool
+
+	// MaxDecompressedBodySize limits size of gzip/deflate request body
+	// after decompression, DefaultMaxDecompressedBodySize if zero
+	MaxDecompressedBodySize int64
 }
 
 // DefaultHost is default application host value
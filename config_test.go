@@ -17,3 +17,15 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("Debug filed should be default")
 	}
 }
+
+func TestConfigNetwork(t *testing.T) {
+	cnf := DefaultConfig()
+	if network, address := cnf.network(); network != "tcp" || address != "127.0.0.1:4321" {
+		t.Errorf("Default network should be tcp host:port")
+	}
+
+	cnf.Address = "unix:/tmp/banjo.sock"
+	if network, address := cnf.network(); network != "unix" || address != "/tmp/banjo.sock" {
+		t.Errorf("unix: address should use unix network")
+	}
+}
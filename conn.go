@@ -0,0 +1,314 @@
+package banjo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxHeaderBytes is upper limit for the size of request header block
+const MaxHeaderBytes = 1 << 20
+
+// DefaultMaxBodyBytes is default upper limit for the size of request body
+const DefaultMaxBodyBytes = 10 << 20
+
+// rejectWriteTimeout is write deadline for error
+// responses sent when request can't be read
+const rejectWriteTimeout = 5 * time.Second
+
+// readChunkSize is size of single read from connection
+const readChunkSize = 4096
+
+// errRequestTimeout is returned by readRequest when
+// request wasn't received in configured time
+var errRequestTimeout = errors.New("request timeout")
+
+// errIdleTimeout is returned by readRequest when
+// client didn't send anything in configured time
+var errIdleTimeout = errors.New("idle timeout")
+
+// errHeaderTooLarge is returned by readRequest when
+// header block exceeds MaxHeaderBytes
+var errHeaderTooLarge = errors.New("request header too large")
+
+// errBodyTooLarge is returned when request body
+// exceeds MaxBodyBytes or MaxDecompressedBodySize
+var errBodyTooLarge = errors.New("request body too large")
+
+// readRequest function
+//
+// Reads raw HTTP Request from connection applying
+// IdleTimeout, ReadHeaderTimeout & ReadTimeout deadlines,
+// body is read according to Content-Length header
+//
+// Params:
+// - conn {net.Conn}
+//
+// Response:
+// - data {string} Raw HTTP Request
+// - err  {error}
+//
+func (banjo Banjo) readRequest(conn net.Conn) (string, error) {
+	config := banjo.config
+	start := time.Now()
+	data := []byte{}
+	chunk := make([]byte, readChunkSize)
+
+	if config.IdleTimeout > 0 {
+		conn.SetReadDeadline(start.Add(config.IdleTimeout))
+	} else {
+		setReadDeadline(conn, start, config.ReadHeaderTimeout, config.ReadTimeout)
+	}
+
+	headerEnd := -1
+
+	for headerEnd < 0 {
+		n, err := conn.Read(chunk)
+
+		if n > 0 && len(data) == 0 && config.IdleTimeout > 0 {
+			start = time.Now()
+			setReadDeadline(conn, start, config.ReadHeaderTimeout, config.ReadTimeout)
+		}
+
+		data = append(data, chunk[:n]...)
+
+		if index := bytes.Index(data, []byte(DubSeparator)); index >= 0 {
+			headerEnd = index + len(DubSeparator)
+			break
+		}
+
+		if len(data) > MaxHeaderBytes {
+			return "", errHeaderTooLarge
+		}
+
+		if err != nil {
+			return string(data), readError(err, len(data) == 0)
+		}
+	}
+
+	setReadDeadline(conn, start, config.ReadTimeout, 0)
+
+	length := requestContentLength(string(data[:headerEnd]))
+
+	limit := config.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+
+	if length > limit {
+		return "", errBodyTooLarge
+	}
+
+	for int64(len(data)-headerEnd) < length {
+		n, err := conn.Read(chunk)
+		data = append(data, chunk[:n]...)
+
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return string(data), readError(err, false)
+		}
+	}
+
+	conn.SetReadDeadline(time.Time{})
+
+	if config.WriteTimeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(config.WriteTimeout))
+	}
+
+	return string(data), nil
+}
+
+// clearDeadlines function
+//
+// Removes connection deadlines for long-lived
+// responses like Server-Sent Events & WebSocket
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (ctx *Context) clearDeadlines() {
+	if ctx.conn != nil {
+		ctx.conn.SetDeadline(time.Time{})
+	}
+}
+
+// setReadDeadline function
+//
+// Sets read deadline using first non zero timeout,
+// removes deadline if both are zero
+//
+// Params:
+// - conn     {net.Conn}
+// - start    {time.Time}
+// - timeout  {time.Duration}
+// - fallback {time.Duration}
+//
+// Response:
+// - None
+//
+func setReadDeadline(conn net.Conn, start time.Time, timeout time.Duration, fallback time.Duration) {
+	if timeout <= 0 {
+		timeout = fallback
+	}
+
+	if timeout <= 0 {
+		conn.SetReadDeadline(time.Time{})
+		return
+	}
+
+	conn.SetReadDeadline(start.Add(timeout))
+}
+
+// readError function
+//
+// Converts connection read error to request error
+//
+// Params:
+// - err   {error}
+// - empty {bool} true if nothing was received
+//
+// Response:
+// - err {error}
+//
+func readError(err error, empty bool) error {
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		if empty {
+			return errIdleTimeout
+		}
+
+		return errRequestTimeout
+	}
+
+	return err
+}
+
+// requestContentLength function
+//
+// Returns Content-Length value from raw header block
+//
+// Params:
+// - header {string} Raw HTTP Request header block
+//
+// Response:
+// - length {int64} zero if header is missing or invalid,
+//   math.MaxInt64 if value overflows
+//
+func requestContentLength(header string) int64 {
+	for _, line := range strings.Split(header, Separator) {
+		index := strings.Index(line, ":")
+		if index < 0 || !strings.EqualFold(strings.TrimSpace(line[:index]), "Content-Length") {
+			continue
+		}
+
+		length, err := strconv.ParseInt(strings.TrimSpace(line[index+1:]), 10, 64)
+		if errors.Is(err, strconv.ErrRange) && length > 0 {
+			return math.MaxInt64
+		}
+
+		if err != nil || length < 0 {
+			return 0
+		}
+
+		return length
+	}
+
+	return 0
+}
+
+// connWatcher struct
+//
+// Background read which detects client disconnect
+// while request is handled
+//
+type connWatcher struct {
+	conn    net.Conn
+	abort   chan struct{}
+	done    chan struct{}
+	pending []byte
+}
+
+// watchConn function
+//
+// Starts background read on connection,
+// request context is cancelled when client disconnects
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (ctx *Context) watchConn() {
+	if ctx.conn == nil || ctx.cancel == nil {
+		return
+	}
+
+	watcher := &connWatcher{
+		conn:  ctx.conn,
+		abort: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	ctx.watcher = watcher
+	cancel := ctx.cancel
+
+	go func() {
+		defer close(watcher.done)
+
+		data := make([]byte, 1)
+		n, err := watcher.conn.Read(data)
+
+		if n > 0 {
+			watcher.pending = data[:n]
+			return
+		}
+
+		select {
+		case <-watcher.abort:
+			return
+		default:
+		}
+
+		if err != nil {
+			cancel()
+		}
+	}()
+}
+
+// stopWatching function
+//
+// Stops background read, so connection can be read
+// by protocol handlers like WebSocket
+//
+// Params:
+// - None
+//
+// Response:
+// - pending {[]byte} data received by background read
+//
+func (ctx *Context) stopWatching() []byte {
+	watcher := ctx.watcher
+	if watcher == nil {
+		return nil
+	}
+
+	ctx.watcher = nil
+	close(watcher.abort)
+
+	watcher.conn.SetReadDeadline(time.Unix(1, 0))
+	<-watcher.done
+	watcher.conn.SetReadDeadline(time.Time{})
+
+	return watcher.pending
+}
@@ -0,0 +1,120 @@
+package banjo
+
+import (
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestHeaderTimeout(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.ReadHeaderTimeout = 50 * time.Millisecond
+	app := Create(cnf)
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /foo HTTP/1.1\r\n"))
+	data, _ := ioutil.ReadAll(client)
+
+	if !strings.HasPrefix(string(data), "HTTP/1.1 408\r\n") {
+		t.Errorf("Status should be 408")
+	}
+}
+
+func TestIdleTimeoutClosesSilently(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.IdleTimeout = 50 * time.Millisecond
+	app := Create(cnf)
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	data, err := ioutil.ReadAll(client)
+
+	if err != nil || len(data) != 0 {
+		t.Errorf("Idle connection should be closed without response")
+	}
+}
+
+func TestRequestReadInParts(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/foo", func(ctx *Context) {
+		ctx.HTML(ctx.Request.Params)
+	})
+
+	body := strings.Repeat("a", 10000)
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	go func() {
+		client.Write([]byte("POST /foo HTTP/1.1\r\nContent-Type: text/plain\r\n"))
+		client.Write([]byte("Content-Length: 10000\r\n\r\n"))
+		client.Write([]byte(body[:5000]))
+		client.Write([]byte(body[5000:]))
+	}()
+
+	data, _ := ioutil.ReadAll(client)
+
+	if !strings.HasSuffix(string(data), "\r\n\r\n"+body) {
+		t.Errorf("Whole body should be read")
+	}
+}
+
+func TestRequestBodyTooLarge(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.MaxBodyBytes = 1024
+	app := Create(cnf)
+	app.Post("/foo", func(ctx *Context) {
+		t.Errorf("Closure shouldn't be called")
+	})
+
+	for _, length := range []string{"1025", "10000000000", "99999999999999999999"} {
+		client, server := net.Pipe()
+		go app.handleRequest(server)
+
+		client.Write([]byte("POST /foo HTTP/1.1\r\nContent-Length: " + length + "\r\n\r\n"))
+		data, _ := ioutil.ReadAll(client)
+
+		if !strings.HasPrefix(string(data), "HTTP/1.1 413\r\n") {
+			t.Errorf("Content-Length %s should be answered with 413", length)
+		}
+	}
+}
+
+func TestRequestContentLength(t *testing.T) {
+	if requestContentLength("POST / HTTP/1.1\r\ncontent-length: 12\r\n\r\n") != 12 {
+		t.Errorf("Content-Length should be parsed case insensitive")
+	}
+
+	if requestContentLength("GET / HTTP/1.1\r\n\r\n") != 0 {
+		t.Errorf("Missing Content-Length should be zero")
+	}
+}
+
+func TestClientDisconnectCancelsContext(t *testing.T) {
+	app := Create(DefaultConfig())
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	app.Get("/slow", func(ctx *Context) {
+		close(started)
+		<-ctx.Context().Done()
+		close(cancelled)
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /slow HTTP/1.1\r\n\r\n"))
+	<-started
+	client.Close()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Request context should be cancelled after disconnect")
+	}
+}
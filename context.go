@@ -1,8 +1,14 @@
 package banjo
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 )
 
 // Context struct
@@ -12,6 +18,25 @@ import (
 type Context struct {
 	Request  Request
 	Response Response
+
+	conn     net.Conn
+	writer   *ResponseWriter
+	hijacked bool
+	tlsState *tls.ConnectionState
+
+	httpWriter  http.ResponseWriter
+	httpRequest *http.Request
+
+	requestCtx context.Context
+	cancel     context.CancelFunc
+	watcher    *connWatcher
+
+	values *valueStore
+
+	remote  string
+	proxies []*net.IPNet
+
+	hooks []func(ctx *Context)
 }
 
 // JSON function
@@ -105,3 +130,133 @@ func (ctx *Context) InternalServerError() {
 	ctx.Response.Status = 500
 	ctx.Response.Body = "Internal Server Error"
 }
+
+// Writer function
+//
+// Returns ResponseWriter for streaming response body,
+// ctx.Response Status & Headers should be set before first Write,
+// they are sent to the client together with first part of body
+// Once body is streamed, Response.Body is ignored, so ctx.JSON,
+// ctx.HTML & ctx.RedirectTo calls after first Write have no effect
+// Example usage:
+// w := ctx.Writer()
+// fmt.Fprintf(w, "id,name\n")
+// w.Flush()
+//
+// Params:
+// - None
+//
+// Response:
+// - writer {*ResponseWriter}
+//
+func (ctx *Context) Writer() *ResponseWriter {
+	if ctx.writer == nil {
+		ctx.writer = &ResponseWriter{ctx: ctx}
+
+		if ctx.conn != nil {
+			ctx.writer.buffer = bufio.NewWriter(ctx.conn)
+		} else if ctx.httpWriter != nil {
+			ctx.writer.buffer = bufio.NewWriter(ctx.httpWriter)
+			ctx.writer.target = ctx.httpWriter
+		}
+	}
+
+	return ctx.writer
+}
+
+// Stream function
+//
+// Streams data from reader to the client
+// with given Content-Type without buffering whole body
+// Example usage:
+// ctx.Stream("text/csv", file)
+//
+// Params:
+// - contentType {string}    Content-Type header value
+// - reader      {io.Reader} Response body source
+//
+// Response:
+// - err {error} returns error if something went wrong
+//
+func (ctx *Context) Stream(contentType string, reader io.Reader) error {
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	ctx.Response.Headers["Content-Type"] = contentType
+
+	err := copyStream(ctx.Writer(), reader)
+
+	if err != nil {
+		logger := CreateLogger()
+		str := fmt.Sprintf("Error while streaming response:\nError: %v", err)
+		logger.Error(str)
+	}
+
+	return err
+}
+
+// Context function
+//
+// Returns request context, it's cancelled when client
+// disconnects, server shuts down or request times out
+//
+// Params:
+// - None
+//
+// Response:
+// - ctx {context.Context}
+//
+func (ctx *Context) Context() context.Context {
+	if ctx.requestCtx == nil {
+		return context.Background()
+	}
+
+	return ctx.requestCtx
+}
+
+// SetContext function
+//
+// Replaces request context, new context should be
+// derived from ctx.Context() to keep cancellation
+//
+// Params:
+// - c {context.Context}
+//
+// Response:
+// - None
+//
+func (ctx *Context) SetContext(c context.Context) {
+	ctx.requestCtx = c
+}
+
+// WithValue function
+//
+// Adds value to request context, so it's available
+// for the next closures & functions accepting context.Context
+//
+// Params:
+// - key   {interface{}}
+// - value {interface{}}
+//
+// Response:
+// - None
+//
+func (ctx *Context) WithValue(key interface{}, value interface{}) {
+	ctx.requestCtx = context.WithValue(ctx.Context(), key, value)
+}
+
+// onHeaders function
+//
+// Registers hook which runs right before
+// response headers are written
+//
+// Params:
+// - hook {func(ctx *Context)}
+//
+// Response:
+// - None
+//
+func (ctx *Context) onHeaders(hook func(ctx *Context)) {
+	ctx.hooks = append(ctx.hooks, hook)
+}
@@ -0,0 +1,41 @@
+package banjo
+
+import "net/http"
+
+// Cookie function
+//
+// Returns value of request cookie
+//
+// Params:
+// - name {string} cookie name
+//
+// Response:
+// - value {string} cookie value or empty string
+//
+func (request Request) Cookie(name string) string {
+	r := http.Request{Header: http.Header{"Cookie": {request.Header("Cookie")}}}
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+// SetCookie function
+//
+// Adds Set-Cookie header to response,
+// several cookies can be set for one response
+// Example usage:
+// ctx.SetCookie(&http.Cookie{Name: "session", Value: id, HttpOnly: true})
+//
+// Params:
+// - cookie {*http.Cookie}
+//
+// Response:
+// - None
+//
+func (ctx *Context) SetCookie(cookie *http.Cookie) {
+	ctx.Response.Cookies = append(ctx.Response.Cookies, cookie)
+}
@@ -0,0 +1,27 @@
+package banjo
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestCookie(t *testing.T) {
+	request := Request{Headers: map[string]string{"Cookie": "foo=bar; session=abc"}}
+
+	if request.Cookie("session") != "abc" || request.Cookie("missing") != "" {
+		t.Errorf("Cookie value should be returned")
+	}
+}
+
+func TestSetCookieWritesSeveralHeaders(t *testing.T) {
+	ctx := &Context{}
+	ctx.SetCookie(&http.Cookie{Name: "foo", Value: "1"})
+	ctx.SetCookie(&http.Cookie{Name: "bar", Value: "2", HttpOnly: true})
+
+	head := Parser{}.Head(ctx.Response)
+
+	if !strings.Contains(head, "Set-Cookie: foo=1\r\n") || !strings.Contains(head, "Set-Cookie: bar=2; HttpOnly\r\n") {
+		t.Errorf("Each cookie should be written in separate header")
+	}
+}
@@ -0,0 +1,225 @@
+package banjo
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCORSMethods are methods allowed when CORSOptions.AllowedMethods is empty
+var defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD"}
+
+// CORSOptions struct
+//
+// Cross-Origin Resource Sharing configuration
+//
+type CORSOptions struct {
+	// AllowedOrigins are exact origins like "https://example.com",
+	// origins with wildcard like "https://*.example.com" or "*" for any origin
+	AllowedOrigins []string
+
+	// AllowedOriginPatterns are regular expressions matched against origin
+	AllowedOriginPatterns []*regexp.Regexp
+
+	// AllowOriginFunc allows origin if it returns true
+	AllowOriginFunc func(origin string) bool
+
+	// AllowedMethods are methods allowed for cross-origin
+	// requests, GET, POST, PUT, PATCH, DELETE & HEAD if empty
+	AllowedMethods []string
+
+	// AllowedHeaders are request headers allowed for cross-origin
+	// requests, headers requested by preflight are allowed if empty
+	AllowedHeaders []string
+
+	// ExposedHeaders are response headers available to browser scripts
+	ExposedHeaders []string
+
+	// AllowCredentials allows cookies & authorization,
+	// matched origin is sent instead of "*"
+	AllowCredentials bool
+
+	// MaxAge is time browser may cache preflight response
+	MaxAge time.Duration
+}
+
+// CORS function
+//
+// Returns middleware which adds Access-Control-* headers
+// for allowed origins, preflight OPTIONS requests are answered
+// for all registered urls when middleware is added with Use
+// Example usage:
+// app.Use(banjo.CORS(banjo.CORSOptions{AllowedOrigins: []string{"https://*.example.com"}}))
+//
+// Params:
+// - options {CORSOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func CORS(options CORSOptions) Middleware {
+	if len(options.AllowedMethods) == 0 {
+		options.AllowedMethods = defaultCORSMethods
+	}
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			origin := ctx.Request.Header("Origin")
+			method := ctx.Request.Header("Access-Control-Request-Method")
+
+			if ctx.Request.Method == "OPTIONS" && origin != "" && method != "" {
+				next(ctx)
+				options.preflight(ctx, origin, method)
+				return
+			}
+
+			if origin != "" && options.allowOrigin(origin) {
+				headers := options.originHeaders(ctx, origin)
+
+				if len(options.ExposedHeaders) > 0 {
+					headers["Access-Control-Expose-Headers"] = strings.Join(options.ExposedHeaders, ", ")
+				}
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// preflight function
+//
+// Adds preflight headers if origin, method & headers
+// are allowed and url is registered
+//
+// Params:
+// - ctx    {*Context}
+// - origin {string}
+// - method {string} requested method
+//
+// Response:
+// - None
+//
+func (options CORSOptions) preflight(ctx *Context, origin string, method string) {
+	if ctx.Response.Status >= 400 {
+		return
+	}
+
+	requested := splitHeaderList(ctx.Request.Header("Access-Control-Request-Headers"))
+
+	if !options.allowOrigin(origin) || !containsFold(options.AllowedMethods, method) {
+		return
+	}
+
+	if len(options.AllowedHeaders) > 0 {
+		for _, header := range requested {
+			if !containsFold(options.AllowedHeaders, header) {
+				return
+			}
+		}
+	}
+
+	headers := options.originHeaders(ctx, origin)
+	headers["Access-Control-Allow-Methods"] = strings.Join(options.AllowedMethods, ", ")
+
+	if len(requested) > 0 {
+		headers["Access-Control-Allow-Headers"] = strings.Join(requested, ", ")
+	}
+
+	if options.MaxAge > 0 {
+		headers["Access-Control-Max-Age"] = strconv.FormatInt(int64(options.MaxAge/time.Second), 10)
+	}
+
+	for _, name := range []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"} {
+		addVary(headers, name)
+	}
+}
+
+// originHeaders function
+//
+// Adds Access-Control-Allow-Origin & credentials headers
+//
+// Params:
+// - ctx    {*Context}
+// - origin {string} allowed origin
+//
+// Response:
+// - headers {map[string]string} response headers
+//
+func (options CORSOptions) originHeaders(ctx *Context, origin string) map[string]string {
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	headers := ctx.Response.Headers
+
+	if containsFold(options.AllowedOrigins, "*") && !options.AllowCredentials {
+		headers["Access-Control-Allow-Origin"] = "*"
+	} else {
+		headers["Access-Control-Allow-Origin"] = origin
+		addVary(headers, "Origin")
+	}
+
+	if options.AllowCredentials {
+		headers["Access-Control-Allow-Credentials"] = "true"
+	}
+
+	return headers
+}
+
+// allowOrigin function
+//
+// Checks origin against allowed origins, patterns & function
+//
+// Params:
+// - origin {string}
+//
+// Response:
+// - ok {bool}
+//
+func (options CORSOptions) allowOrigin(origin string) bool {
+	for _, allowed := range options.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+
+		if index := strings.Index(allowed, "*"); index >= 0 {
+			prefix, suffix := allowed[:index], allowed[index+1:]
+
+			if len(origin) >= len(prefix)+len(suffix) &&
+				strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+
+	for _, pattern := range options.AllowedOriginPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+
+	return options.AllowOriginFunc != nil && options.AllowOriginFunc(origin)
+}
+
+// containsFold function
+//
+// Checks if list contains value ignoring case
+//
+// Params:
+// - list  {[]string}
+// - value {string}
+//
+// Response:
+// - ok {bool}
+//
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
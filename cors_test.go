@@ -0,0 +1,125 @@
+package banjo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func corsApp(options CORSOptions) Banjo {
+	app := Create(DefaultConfig())
+	app.Use(CORS(options))
+	app.Get("/api", func(ctx *Context) {
+		ctx.JSON(M{"foo": "bar"})
+	})
+
+	return app
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	app := corsApp(CORSOptions{
+		AllowedOrigins: []string{"https://*.example.com"},
+		ExposedHeaders: []string{"X-Total"},
+	})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/api", Headers: map[string]string{"Origin": "https://app.example.com"}})
+	headers := ctx.Response.Headers
+
+	if headers["Access-Control-Allow-Origin"] != "https://app.example.com" || headers["Vary"] != "Origin" {
+		t.Errorf("Allowed origin should be returned")
+	}
+
+	if headers["Access-Control-Expose-Headers"] != "X-Total" {
+		t.Errorf("Exposed headers should be returned")
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/api", Headers: map[string]string{"Origin": "https://evil.com"}})
+	if _, ok := ctx.Response.Headers["Access-Control-Allow-Origin"]; ok {
+		t.Errorf("Not allowed origin should not get CORS headers")
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	app := corsApp(CORSOptions{
+		AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^https://[a-z]+\.test$`)},
+		AllowedHeaders:        []string{"Content-Type", "Authorization"},
+		AllowCredentials:      true,
+		MaxAge:                10 * time.Minute,
+	})
+
+	ctx := testDispatch(app, Request{Method: "OPTIONS", URL: "/api", Headers: map[string]string{
+		"Origin":                         "https://foo.test",
+		"Access-Control-Request-Method":  "PUT",
+		"Access-Control-Request-Headers": "content-type",
+	}})
+	headers := ctx.Response.Headers
+
+	if ctx.Response.Status != 204 || headers["Allow"] != "GET, OPTIONS" {
+		t.Errorf("Preflight should be answered for registered url")
+	}
+
+	if headers["Access-Control-Allow-Origin"] != "https://foo.test" || headers["Access-Control-Allow-Credentials"] != "true" {
+		t.Errorf("Origin & credentials should be allowed")
+	}
+
+	if headers["Access-Control-Allow-Headers"] != "content-type" || headers["Access-Control-Max-Age"] != "600" {
+		t.Errorf("Requested headers & max age should be returned")
+	}
+
+	if headers["Vary"] != "Origin, Access-Control-Request-Method, Access-Control-Request-Headers" {
+		t.Errorf("Preflight should vary by request headers, got %q", headers["Vary"])
+	}
+
+	ctx = testDispatch(app, Request{Method: "OPTIONS", URL: "/api", Headers: map[string]string{
+		"Origin":                         "https://foo.test",
+		"Access-Control-Request-Method":  "GET",
+		"Access-Control-Request-Headers": "X-Secret",
+	}})
+	if _, ok := ctx.Response.Headers["Access-Control-Allow-Origin"]; ok {
+		t.Errorf("Preflight with not allowed header should not get CORS headers")
+	}
+
+	ctx = testDispatch(app, Request{Method: "OPTIONS", URL: "/missing", Headers: map[string]string{
+		"Origin":                        "https://foo.test",
+		"Access-Control-Request-Method": "GET",
+	}})
+	if ctx.Response.Status != 404 {
+		t.Errorf("Preflight for unknown url should be 404")
+	}
+}
+
+func TestCORSWildcardWithoutCredentials(t *testing.T) {
+	app := corsApp(CORSOptions{AllowedOrigins: []string{"*"}})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/api", Headers: map[string]string{"Origin": "https://foo.com"}})
+	if ctx.Response.Headers["Access-Control-Allow-Origin"] != "*" {
+		t.Errorf("Any origin should be allowed with *")
+	}
+}
+
+func TestCORSOriginFunc(t *testing.T) {
+	app := corsApp(CORSOptions{AllowOriginFunc: func(origin string) bool { return origin == "null" }})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/api", Headers: map[string]string{"Origin": "null"}})
+	if ctx.Response.Headers["Access-Control-Allow-Origin"] != "null" {
+		t.Errorf("Origin allowed by func should be returned")
+	}
+}
+
+func TestCORSKeepsVary(t *testing.T) {
+	cors := CORS(CORSOptions{AllowedOrigins: []string{"https://foo.com"}})
+	compress := Compress(CompressOptions{})
+
+	for _, middleware := range [][]Middleware{{cors, compress}, {compress, cors}} {
+		app := Create(DefaultConfig())
+		app.Use(middleware...)
+		app.Get("/api", func(ctx *Context) {
+			ctx.JSON(M{"foo": "bar"})
+		})
+
+		ctx := testDispatch(app, Request{Method: "GET", URL: "/api", Headers: map[string]string{"Origin": "https://foo.com"}})
+		if vary := ctx.Response.Headers["Vary"]; vary != "Origin, Accept-Encoding" {
+			t.Errorf("Vary should be merged, got %q", vary)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package banjo
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// csrfTokenKey is Context store key of CSRF token
+const csrfTokenKey = "banjo.csrf_token"
+
+// csrfTokenSize is number of random bytes in CSRF token
+const csrfTokenSize = 32
+
+// CSRFOptions struct
+//
+// CSRF middleware configuration,
+// empty fields are replaced with defaults
+//
+type CSRFOptions struct {
+	// CookieName is name of token cookie, "_csrf" by default
+	CookieName string
+
+	// FieldName is form field with token, "csrf_token" by default
+	FieldName string
+
+	// HeaderName is header with token, "X-CSRF-Token" by default
+	HeaderName string
+
+	// CookiePath is token cookie path, "/" by default
+	CookiePath string
+
+	// MaxAge is token cookie lifetime, session cookie if zero
+	MaxAge time.Duration
+
+	// SameSite is token cookie SameSite mode, Lax by default
+	SameSite http.SameSite
+
+	// SkipPaths are url prefixes without CSRF check like webhooks
+	SkipPaths []string
+}
+
+// CSRF function
+//
+// Returns middleware protecting from cross-site request forgery
+// by double-submit cookie, token from form field or header
+// should match token cookie for POST, PUT, PATCH & DELETE requests,
+// 403 is sent on mismatch
+// Example usage:
+// app.Use(banjo.CSRF(banjo.CSRFOptions{SkipPaths: []string{"/webhooks"}}))
+//
+// Params:
+// - options {CSRFOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func CSRF(options CSRFOptions) Middleware {
+	if options.CookieName == "" {
+		options.CookieName = "_csrf"
+	}
+
+	if options.FieldName == "" {
+		options.FieldName = "csrf_token"
+	}
+
+	if options.HeaderName == "" {
+		options.HeaderName = "X-CSRF-Token"
+	}
+
+	if options.CookiePath == "" {
+		options.CookiePath = "/"
+	}
+
+	if options.SameSite == 0 {
+		options.SameSite = http.SameSiteLaxMode
+	}
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			if options.skip(ctx.Request.URL) {
+				next(ctx)
+				return
+			}
+
+			token := ctx.Request.Cookie(options.CookieName)
+			valid := validCSRFToken(token)
+
+			if !valid {
+				var err error
+
+				if token, err = csrfToken(); err != nil {
+					CreateLogger().Error(fmt.Sprintf("Error while generating CSRF token:\nError: %v", err))
+					ctx.InternalServerError()
+					return
+				}
+
+				options.setCookie(ctx, token)
+			}
+
+			ctx.Set(csrfTokenKey, token)
+
+			switch ctx.Request.Method {
+			case "GET", "HEAD", "OPTIONS", "TRACE":
+				next(ctx)
+				return
+			}
+
+			submitted := ctx.Request.Header(options.HeaderName)
+			if submitted == "" {
+				submitted = ctx.Request.MapParams[options.FieldName]
+			}
+
+			if !valid || subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+				ctx.Response.Status = http.StatusForbidden
+				ctx.Response.Body = "Forbidden - invalid CSRF token"
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// CSRFToken function
+//
+// Returns CSRF token for forms & JavaScript requests
+// Example usage:
+// `<input type="hidden" name="csrf_token" value="` + ctx.CSRFToken() + `">`
+//
+// Params:
+// - None
+//
+// Response:
+// - token {string} empty if CSRF middleware isn't used
+//
+func (ctx *Context) CSRFToken() string {
+	token, _ := GetAs[string](ctx, csrfTokenKey)
+
+	return token
+}
+
+// skip function
+//
+// Checks if url starts with one of SkipPaths
+//
+// Params:
+// - url {string}
+//
+// Response:
+// - ok {bool}
+//
+func (options CSRFOptions) skip(url string) bool {
+	for _, path := range options.SkipPaths {
+		path = strings.TrimSuffix(path, "/")
+
+		if url == path || strings.HasPrefix(url, path+"/") || strings.HasPrefix(url, path+"?") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setCookie function
+//
+// Sends new token cookie
+//
+// Params:
+// - ctx   {*Context}
+// - token {string}
+//
+// Response:
+// - None
+//
+func (options CSRFOptions) setCookie(ctx *Context, token string) {
+	ctx.SetCookie(&http.Cookie{
+		Name:     options.CookieName,
+		Value:    token,
+		Path:     options.CookiePath,
+		MaxAge:   int(options.MaxAge / time.Second),
+		HttpOnly: true,
+		Secure:   ctx.Scheme() == "https",
+		SameSite: options.SameSite,
+	})
+}
+
+// csrfToken function
+//
+// Generates random token
+//
+// Params:
+// - None
+//
+// Response:
+// - token {string}
+// - err   {error} random source error
+//
+func csrfToken() (string, error) {
+	data := make([]byte, csrfTokenSize)
+
+	if _, err := io.ReadFull(rand.Reader, data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// validCSRFToken function
+//
+// Checks token format
+//
+// Params:
+// - token {string}
+//
+// Response:
+// - ok {bool}
+//
+func validCSRFToken(token string) bool {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+
+	return err == nil && len(data) == csrfTokenSize
+}
@@ -0,0 +1,82 @@
+package banjo
+
+import "testing"
+
+func csrfApp() Banjo {
+	app := Create(DefaultConfig())
+	app.Use(CSRF(CSRFOptions{SkipPaths: []string{"/webhooks"}}))
+
+	form := func(ctx *Context) {
+		ctx.HTML(ctx.CSRFToken())
+	}
+
+	app.Get("/form", form)
+	app.Post("/form", form)
+	app.Post("/webhooks/github", form)
+
+	return app
+}
+
+func TestCSRFIssuesToken(t *testing.T) {
+	app := csrfApp()
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/form"})
+
+	if len(ctx.Response.Cookies) != 1 || ctx.Response.Cookies[0].Value != ctx.Response.Body || !ctx.Response.Cookies[0].HttpOnly {
+		t.Errorf("Token cookie should be set & token available in closure")
+	}
+}
+
+func TestCSRFValidatesUnsafeMethods(t *testing.T) {
+	app := csrfApp()
+	token, _ := csrfToken()
+	cookie := "_csrf=" + token
+
+	ctx := testDispatch(app, Request{Method: "POST", URL: "/form", Headers: map[string]string{"Cookie": cookie}})
+
+	if ctx.Response.Status != 403 {
+		t.Errorf("Request without token should be forbidden")
+	}
+
+	ctx = testDispatch(app, Request{
+		Method:    "POST",
+		URL:       "/form",
+		Headers:   map[string]string{"Cookie": cookie},
+		MapParams: map[string]string{"csrf_token": token},
+	})
+
+	if ctx.Response.Status != 200 || len(ctx.Response.Cookies) != 0 {
+		t.Errorf("Request with form token should be allowed")
+	}
+
+	ctx = testDispatch(app, Request{Method: "POST", URL: "/form", Headers: map[string]string{"Cookie": cookie, "X-CSRF-Token": token}})
+
+	if ctx.Response.Status != 200 {
+		t.Errorf("Request with header token should be allowed")
+	}
+
+	ctx = testDispatch(app, Request{Method: "POST", URL: "/form", Headers: map[string]string{"X-CSRF-Token": token}})
+
+	if ctx.Response.Status != 403 {
+		t.Errorf("Request without token cookie should be forbidden")
+	}
+}
+
+func TestCSRFSkipsPaths(t *testing.T) {
+	app := csrfApp()
+
+	ctx := testDispatch(app, Request{Method: "POST", URL: "/webhooks/github"})
+
+	if ctx.Response.Status != 200 {
+		t.Errorf("Skipped path should not be checked")
+	}
+}
+
+func TestCSRFTokenFailure(t *testing.T) {
+	failRandom(t)
+
+	ctx := testDispatch(csrfApp(), Request{Method: "GET", URL: "/form"})
+	if ctx.Response.Status != 500 || len(ctx.Response.Cookies) != 0 {
+		t.Errorf("Request should fail when token can't be generated")
+	}
+}
@@ -0,0 +1,164 @@
+package banjo
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxDecompressedBodySize is default upper limit
+// for the size of decompressed request body
+const DefaultMaxDecompressedBodySize = 10 << 20
+
+// errUnsupportedEncoding is returned by decodeRequest
+// when Content-Encoding isn't gzip, deflate or identity
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// decodeRequest function
+//
+// Decompresses gzip/deflate request body, updates Body, Params,
+// MapParams & Files and removes Content-Encoding header,
+// body as received is kept in RawBody
+//
+// Params:
+// - request {*Request}
+//
+// Response:
+// - err {error} errUnsupportedEncoding, errBodyTooLarge or decoding error
+//
+func (banjo Banjo) decodeRequest(request *Request) error {
+	if !encodedBody(request.Headers) {
+		return nil
+	}
+
+	limit := banjo.config.MaxDecompressedBodySize
+	if limit <= 0 {
+		limit = DefaultMaxDecompressedBodySize
+	}
+
+	encodings := splitHeaderList(request.Header("Content-Encoding"))
+	body := request.Body
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		data, err := decodeBody(body, strings.ToLower(encodings[i]), limit)
+		if err != nil {
+			return err
+		}
+
+		body = data
+	}
+
+	delete(request.Headers, headerKey(request.Headers, "Content-Encoding"))
+	if key := headerKey(request.Headers, "Content-Length"); request.Headers[key] != "" {
+		request.Headers[key] = strconv.Itoa(len(body))
+	}
+
+	request.RawBody = request.Body
+	request.Body = body
+	request.Params = string(body)
+	request.MapParams, request.Files = parseParams(request.Params, request.Header("Content-Type"))
+
+	return nil
+}
+
+// decodeBody function
+//
+// Decompresses body encoded with single content coding,
+// deflate accepts both zlib & raw deflate streams
+//
+// Params:
+// - body     {[]byte}
+// - encoding {string} lower case content coding
+// - limit    {int64} maximal size of decompressed body
+//
+// Response:
+// - body {[]byte}
+// - err  {error}
+//
+func decodeBody(body []byte, encoding string, limit int64) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+
+	switch encoding {
+	case "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader, err = flate.NewReader(bytes.NewReader(body)), nil
+		}
+	default:
+		return nil, errUnsupportedEncoding
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, errBodyTooLarge
+	}
+
+	return data, nil
+}
+
+// encodedBody function
+//
+// Reports whether request body has Content-Encoding
+// other than identity
+//
+// Params:
+// - headers {map[string]string} request headers
+//
+// Response:
+// - encoded {bool}
+//
+func encodedBody(headers map[string]string) bool {
+	for _, encoding := range splitHeaderList(Request{Headers: headers}.Header("Content-Encoding")) {
+		if !strings.EqualFold(encoding, "identity") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rejectBody function
+//
+// Answers request which body can't be decompressed
+//
+// Params:
+// - ctx {*Context}
+// - err {error} decodeRequest error
+//
+// Response:
+// - None
+//
+func (banjo Banjo) rejectBody(ctx *Context, err error) {
+	switch err {
+	case errUnsupportedEncoding:
+		ctx.Response = Response{Status: 415, Body: "Unsupported Media Type"}
+	case errBodyTooLarge:
+		ctx.Response = Response{Status: 413, Body: "Payload Too Large"}
+	default:
+		ctx.Response = Response{Status: 400, Body: "Bad Request"}
+	}
+
+	str := fmt.Sprintf("Request body rejected: %s %s\nError: %v", ctx.Request.Method, ctx.Request.URL, err)
+	banjo.logger.Warning(str)
+}
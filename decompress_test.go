@@ -0,0 +1,119 @@
+package banjo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func gzipBody(data string) []byte {
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte(data))
+	writer.Close()
+
+	return buffer.Bytes()
+}
+
+func TestDecodeRequestBody(t *testing.T) {
+	var deflated bytes.Buffer
+
+	writer := zlib.NewWriter(&deflated)
+	writer.Write([]byte("foo=bar"))
+	writer.Close()
+
+	app := Create(DefaultConfig())
+	app.Post("/form", func(ctx *Context) {
+		ctx.Response.Body = ctx.Request.MapParams["foo"] + " " + ctx.Request.Params
+	})
+
+	body := deflated.String()
+	data := "POST /form HTTP/1.1\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Encoding: deflate\r\n\r\n" + body
+	ctx := testContext(app.parser.Request(data))
+	app.dispatch(ctx)
+
+	if ctx.Response.Body != "bar foo=bar" || ctx.Request.Headers["Content-Encoding"] != "" {
+		t.Errorf("Deflate body should be decompressed before parsing params")
+	}
+}
+
+func TestDecodeRequestErrors(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.MaxDecompressedBodySize = 1024
+
+	app := Create(cnf)
+	app.Post("/json", func(ctx *Context) {
+		ctx.Response.Body = ctx.Request.Params
+	})
+
+	cases := []struct {
+		encoding string
+		body     []byte
+		status   int
+	}{
+		{"gzip", gzipBody(`{"foo":"bar"}`), 200},
+		{"gzip", gzipBody(strings.Repeat("0", 2048)), 413},
+		{"gzip", []byte("not gzip"), 400},
+		{"br", []byte("foo"), 415},
+		{"identity", []byte(`{}`), 200},
+	}
+
+	for _, c := range cases {
+		ctx := testContext(Request{
+			Method:  "POST",
+			URL:     "/json",
+			Headers: map[string]string{"Content-Encoding": c.encoding, "Content-Type": "application/json"},
+			Body:    c.body,
+			Params:  string(c.body),
+		})
+		app.dispatch(ctx)
+
+		if status := ctx.Response.Status; status != c.status && !(status == 0 && c.status == 200) {
+			t.Errorf("%s body should be answered with %d, got %d", c.encoding, c.status, status)
+		}
+	}
+}
+
+func TestDecodeRequestServeHTTP(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/json", func(ctx *Context) {
+		ctx.Response.Body = ctx.Request.Params
+	})
+
+	request, _ := http.NewRequest("POST", "/json", bytes.NewReader(gzipBody(`{"foo":"bar"}`)))
+	request.Header.Set("Content-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, request)
+
+	if recorder.Body.String() != `{"foo":"bar"}` {
+		t.Errorf("Gzip body should be decompressed, got %q", recorder.Body.String())
+	}
+}
+
+func TestDecodeRequestLowerCaseHeaders(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/form", func(ctx *Context) {
+		ctx.Response.Body = ctx.Request.MapParams["foo"] + " " + ctx.Request.Header("Content-Length")
+	})
+
+	body := gzipBody("foo=bar")
+	ctx := testDispatch(app, Request{Method: "POST", URL: "/form", Body: body, Headers: map[string]string{
+		"content-type":     "application/x-www-form-urlencoded",
+		"content-encoding": "gzip",
+		"content-length":   strconv.Itoa(len(body)),
+	}})
+
+	if ctx.Response.Body != "bar 7" || ctx.Request.Header("Content-Encoding") != "" {
+		t.Errorf("Lower case headers should be decoded, got %q", ctx.Response.Body)
+	}
+
+	if !bytes.Equal(ctx.Request.RawBody, body) {
+		t.Errorf("Raw body should be kept")
+	}
+}
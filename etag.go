@@ -0,0 +1,322 @@
+package banjo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETagOptions struct
+//
+// ETag middleware configuration
+//
+type ETagOptions struct {
+	// Weak marks generated ETags as weak validators
+	Weak bool
+}
+
+// ETag function
+//
+// Returns middleware generating ETag from buffered response body
+// unless closure set it with SetETag, conditional headers are
+// evaluated against ETag & Last-Modified: If-None-Match &
+// If-Modified-Since are answered with 304, failed If-Match &
+// If-Unmodified-Since with 412
+// Example usage:
+// app.Use(banjo.ETag(banjo.ETagOptions{}))
+//
+// Params:
+// - options {ETagOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func ETag(options ETagOptions) Middleware {
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			ctx.onHeaders(options.validate)
+			next(ctx)
+		}
+	}
+}
+
+// SetETag function
+//
+// Sets ETag response header, tag which isn't
+// quoted on both ends is quoted
+//
+// Params:
+// - tag {string} e.g. "v1", `"v1"` or `W/"v1"`
+//
+// Response:
+// - None
+//
+func (ctx *Context) SetETag(tag string) {
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	opaque := strings.TrimPrefix(tag, "W/")
+	if len(opaque) < 2 || !strings.HasPrefix(opaque, `"`) || !strings.HasSuffix(opaque, `"`) {
+		tag = tag[:len(tag)-len(opaque)] + `"` + strings.Trim(opaque, `"`) + `"`
+	}
+
+	ctx.Response.Headers["ETag"] = tag
+}
+
+// SetLastModified function
+//
+// Sets Last-Modified response header,
+// zero time is ignored
+//
+// Params:
+// - modified {time.Time}
+//
+// Response:
+// - None
+//
+func (ctx *Context) SetLastModified(modified time.Time) {
+	if modified.IsZero() {
+		return
+	}
+
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	ctx.Response.Headers["Last-Modified"] = modified.UTC().Format(http.TimeFormat)
+}
+
+// CheckPreconditions function
+//
+// Evaluates conditional request headers against ETag & Last-Modified
+// set by closure, sets 304 or 412 response when they don't pass,
+// call it before changing resource or streaming response
+// Example usage:
+// ctx.SetETag(article.Version)
+// if !ctx.CheckPreconditions() {
+//   return
+// }
+//
+// Params:
+// - None
+//
+// Response:
+// - ok {bool} false when response was already set
+//
+func (ctx *Context) CheckPreconditions() bool {
+	status := evaluatePreconditions(ctx.Request, ctx.Response.Headers)
+
+	switch status {
+	case 304:
+		notModified(&ctx.Response)
+	case 412:
+		preconditionFailed(&ctx.Response)
+	}
+
+	return status == 0
+}
+
+// validate function
+//
+// Adds generated ETag & evaluates conditional headers
+// right before headers are written
+//
+// Params:
+// - ctx {*Context}
+//
+// Response:
+// - None
+//
+func (options ETagOptions) validate(ctx *Context) {
+	response := &ctx.Response
+
+	if response.Status < 200 || response.Status > 299 {
+		return
+	}
+
+	streaming := ctx.writer != nil && ctx.writer.Streaming()
+
+	if _, ok := response.Headers["ETag"]; !ok && !streaming && response.Status != 204 {
+		response.Headers["ETag"] = generateETag(response.Body, options.Weak)
+	}
+
+	// headers of streamed response can't be changed to 304 or 412,
+	// such closures use CheckPreconditions
+	if streaming {
+		return
+	}
+
+	ctx.CheckPreconditions()
+}
+
+// evaluatePreconditions function
+//
+// Evaluates conditional headers in RFC 9110 order
+//
+// Params:
+// - request {Request}
+// - headers {map[string]string} response headers
+//
+// Response:
+// - status {int} 304, 412 or 0 when request passes
+//
+func evaluatePreconditions(request Request, headers map[string]string) int {
+	etag := headers["ETag"]
+	modified, modifiedErr := http.ParseTime(headers["Last-Modified"])
+	safe := request.Method == "GET" || request.Method == "HEAD"
+
+	if match := request.Header("If-Match"); match != "" {
+		if !matchETag(match, etag, false) {
+			return 412
+		}
+	} else if since := request.Header("If-Unmodified-Since"); since != "" && modifiedErr == nil {
+		if t, err := http.ParseTime(since); err == nil && modified.After(t) {
+			return 412
+		}
+	}
+
+	if match := request.Header("If-None-Match"); match != "" {
+		if !matchETag(match, etag, true) {
+			return 0
+		}
+
+		if safe {
+			return 304
+		}
+
+		return 412
+	}
+
+	if since := request.Header("If-Modified-Since"); since != "" && safe && modifiedErr == nil {
+		if t, err := http.ParseTime(since); err == nil && !modified.After(t) {
+			return 304
+		}
+	}
+
+	return 0
+}
+
+// matchETag function
+//
+// Checks ETag against list from If-Match or If-None-Match
+//
+// Params:
+// - list {string} header value, "*" matches any ETag
+// - etag {string} response ETag
+// - weak {bool}   use weak comparison
+//
+// Response:
+// - ok {bool}
+//
+func matchETag(list string, etag string, weak bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	if etag == "" {
+		return false
+	}
+
+	if !weak && strings.HasPrefix(etag, "W/") {
+		return false
+	}
+
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if !weak && strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		if strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generateETag function
+//
+// Returns quoted SHA-256 based ETag of body
+//
+// Params:
+// - body {string}
+// - weak {bool}
+//
+// Response:
+// - etag {string}
+//
+func generateETag(body string, weak bool) string {
+	sum := sha256.Sum256([]byte(body))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	if weak {
+		return "W/" + etag
+	}
+
+	return etag
+}
+
+// weakenETag function
+//
+// Marks strong ETag as weak, used when body
+// is transformed e.g. compressed
+//
+// Params:
+// - headers {map[string]string} response headers
+//
+// Response:
+// - None
+//
+func weakenETag(headers map[string]string) {
+	if etag, ok := headers["ETag"]; ok && etag != "" && !strings.HasPrefix(etag, "W/") {
+		headers["ETag"] = "W/" + etag
+	}
+}
+
+// notModified function
+//
+// Turns response into 304 keeping validator
+// & caching headers
+//
+// Params:
+// - response {*Response}
+//
+// Response:
+// - None
+//
+func notModified(response *Response) {
+	for _, name := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+		delete(response.Headers, name)
+	}
+
+	response.Status = 304
+	response.Body = ""
+}
+
+// preconditionFailed function
+//
+// Turns response into 412
+//
+// Params:
+// - response {*Response}
+//
+// Response:
+// - None
+//
+func preconditionFailed(response *Response) {
+	if response.Headers == nil {
+		response.Headers = make(map[string]string)
+	}
+
+	delete(response.Headers, "Content-Length")
+	delete(response.Headers, "Content-Encoding")
+
+	response.Headers["Content-Type"] = "text/plain"
+	response.Status = 412
+	response.Body = "Precondition Failed"
+}
@@ -0,0 +1,126 @@
+package banjo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluatePreconditions(t *testing.T) {
+	modified := "Mon, 02 Jan 2006 15:04:05 GMT"
+	headers := map[string]string{"ETag": `"v2"`, "Last-Modified": modified}
+
+	cases := []struct {
+		method  string
+		headers map[string]string
+		status  int
+	}{
+		{"GET", map[string]string{}, 0},
+		{"GET", map[string]string{"If-None-Match": `"v1", W/"v2"`}, 304},
+		{"GET", map[string]string{"If-None-Match": `"v1"`}, 0},
+		{"GET", map[string]string{"If-None-Match": `"v1"`, "If-Modified-Since": modified}, 0},
+		{"GET", map[string]string{"If-Modified-Since": modified}, 304},
+		{"GET", map[string]string{"If-Modified-Since": "Sun, 01 Jan 2006 00:00:00 GMT"}, 0},
+		{"PUT", map[string]string{"If-Match": `"v1"`}, 412},
+		{"PUT", map[string]string{"If-Match": `W/"v2"`}, 412},
+		{"PUT", map[string]string{"If-Match": `"v2"`}, 0},
+		{"PUT", map[string]string{"If-Match": "*"}, 0},
+		{"PUT", map[string]string{"If-None-Match": "*"}, 412},
+		{"PUT", map[string]string{"If-Unmodified-Since": "Sun, 01 Jan 2006 00:00:00 GMT"}, 412},
+		{"PUT", map[string]string{"If-Unmodified-Since": modified}, 0},
+		{"GET", map[string]string{"if-none-match": `"v2"`}, 304},
+		{"GET", map[string]string{"if-modified-since": modified}, 304},
+		{"PUT", map[string]string{"if-match": `"v1"`}, 412},
+		{"PUT", map[string]string{"if-unmodified-since": "Sun, 01 Jan 2006 00:00:00 GMT"}, 412},
+	}
+
+	for _, c := range cases {
+		if status := evaluatePreconditions(Request{Method: c.method, Headers: c.headers}, headers); status != c.status {
+			t.Errorf("%s %v should be answered with %d, got %d", c.method, c.headers, c.status, status)
+		}
+	}
+}
+
+func TestETagMiddleware(t *testing.T) {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	app := Create(DefaultConfig())
+	app.Use(ETag(ETagOptions{}))
+	app.Get("/", func(ctx *Context) {
+		ctx.SetLastModified(modified)
+		ctx.JSON(M{"foo": "bar"})
+	})
+	app.Put("/", func(ctx *Context) {
+		ctx.SetETag("v1")
+		if !ctx.CheckPreconditions() {
+			return
+		}
+
+		ctx.Response.Status = 204
+	})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/", Headers: map[string]string{}})
+
+	etag := ctx.Response.Headers["ETag"]
+	if etag == "" || etag != generateETag(ctx.Response.Body, false) {
+		t.Fatalf("Strong ETag should be generated from body")
+	}
+
+	if ctx.Response.Headers["Last-Modified"] != "Thu, 02 Jan 2020 03:04:05 GMT" {
+		t.Errorf("Last-Modified should be set in HTTP format")
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/", Headers: map[string]string{"If-None-Match": etag}})
+
+	if ctx.Response.Status != 304 || ctx.Response.Body != "" || ctx.Response.Headers["ETag"] != etag {
+		t.Errorf("Matching If-None-Match should be answered with 304")
+	}
+
+	ctx = testDispatch(app, Request{Method: "PUT", URL: "/", Headers: map[string]string{"If-Match": `"v0"`}})
+
+	if ctx.Response.Status != 412 {
+		t.Errorf("Stale If-Match should be answered with 412, got %d", ctx.Response.Status)
+	}
+}
+
+func TestETagWeakenedByCompress(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Use(ETag(ETagOptions{}), Compress(CompressOptions{MinSize: 1}))
+	app.Get("/", func(ctx *Context) {
+		ctx.HTML(strings.Repeat("<h1>Hello from BANjO!</h1>", 10))
+	})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/", Headers: map[string]string{"Accept-Encoding": "gzip"}})
+
+	etag := ctx.Response.Headers["ETag"]
+	if ctx.Response.Headers["Content-Encoding"] != "gzip" || etag[:2] != "W/" {
+		t.Fatalf("ETag of compressed body should be weak, got %q", etag)
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/", Headers: map[string]string{"Accept-Encoding": "gzip", "If-None-Match": etag}})
+
+	if ctx.Response.Status != 304 || ctx.Response.Body != "" {
+		t.Errorf("Weak ETag should match If-None-Match")
+	}
+}
+
+func TestSetETag(t *testing.T) {
+	cases := map[string]string{
+		"v1":     `"v1"`,
+		`"v1"`:   `"v1"`,
+		`W/"v1"`: `W/"v1"`,
+		`v1"`:    `"v1"`,
+		`"v1`:    `"v1"`,
+		`"`:      `""`,
+		`W/"v1`:  `W/"v1"`,
+	}
+
+	for tag, expected := range cases {
+		ctx := testContext(Request{})
+		ctx.SetETag(tag)
+
+		if etag := ctx.Response.Headers["ETag"]; etag != expected {
+			t.Errorf("ETag %q should be set as %q, got %q", tag, expected, etag)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package banjo
+
+// Group struct
+//
+// Routes sharing url prefix & middleware
+//
+type Group struct {
+	banjo      Banjo
+	prefix     string
+	middleware []Middleware
+}
+
+// Group function
+//
+// Returns routes group with url prefix,
+// middleware wraps every closure added to the group
+// Example usage:
+// admin := app.Group("/admin", banjo.BasicAuthUsers("admin", users))
+// admin.Get("/stats", stats)
+//
+// Params:
+// - prefix     {string} url prefix
+// - middleware {...Middleware}
+//
+// Response:
+// - group {Group}
+//
+func (banjo Banjo) Group(prefix string, middleware ...Middleware) Group {
+	return Group{banjo: banjo, prefix: prefix, middleware: middleware}
+}
+
+// Group function
+//
+// Returns nested group, parent middleware runs first
+//
+// Params:
+// - prefix     {string} url prefix added to parent prefix
+// - middleware {...Middleware}
+//
+// Response:
+// - group {Group}
+//
+func (group Group) Group(prefix string, middleware ...Middleware) Group {
+	all := append(append([]Middleware{}, group.middleware...), middleware...)
+
+	return Group{banjo: group.banjo, prefix: group.prefix + prefix, middleware: all}
+}
+
+// Get function
+// For handling GET Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Get(url string, closure func(ctx *Context)) {
+	group.push("GET", url, closure)
+}
+
+// Post function
+// For handling POST Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Post(url string, closure func(ctx *Context)) {
+	group.push("POST", url, closure)
+}
+
+// Put function
+// For handling PUT Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Put(url string, closure func(ctx *Context)) {
+	group.push("PUT", url, closure)
+}
+
+// Patch function
+// For handling PATCH Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Patch(url string, closure func(ctx *Context)) {
+	group.push("PATCH", url, closure)
+}
+
+// Options function
+// For handling OPTIONS Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Options(url string, closure func(ctx *Context)) {
+	group.push("OPTIONS", url, closure)
+}
+
+// Head function
+// For handling HEAD Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Head(url string, closure func(ctx *Context)) {
+	group.push("HEAD", url, closure)
+}
+
+// Delete function
+// For handling DELETE Requests in group
+//
+// Params:
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)} Closure for handling HTTP Request
+//
+// Response:
+// - None
+//
+func (group Group) Delete(url string, closure func(ctx *Context)) {
+	group.push("DELETE", url, closure)
+}
+
+// push function
+//
+// Adds closure wrapped with group middleware to routes
+//
+// Params:
+// - method  {string} HTTP Request Method
+// - url     {string} HTTP Request URL without group prefix
+// - closure {func(ctx *Context)}
+//
+// Response:
+// - None
+//
+func (group Group) push(method string, url string, closure func(ctx *Context)) {
+	group.banjo.routes.Push(method, group.prefix+url, Chain(closure, group.middleware...))
+}
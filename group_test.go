@@ -0,0 +1,27 @@
+package banjo
+
+import "testing"
+
+func TestGroupNestedMiddleware(t *testing.T) {
+	order := ""
+	mark := func(name string) Middleware {
+		return func(next func(ctx *Context)) func(ctx *Context) {
+			return func(ctx *Context) {
+				order += name
+				next(ctx)
+			}
+		}
+	}
+
+	app := Create(DefaultConfig())
+	v1 := app.Group("/api", mark("a")).Group("/v1", mark("b"))
+	v1.Post("/users", func(ctx *Context) {
+		order += "!"
+	})
+
+	app.dispatch(testContext(Request{Method: "POST", URL: "/api/v1/users"}))
+
+	if order != "ab!" {
+		t.Errorf("Group middleware should run from outer to inner group")
+	}
+}
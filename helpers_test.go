@@ -0,0 +1,39 @@
+package banjo
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+type brokenEntropy struct{}
+
+func (brokenEntropy) Read(data []byte) (int, error) {
+	return 0, errors.New("entropy source failed")
+}
+
+func failRandom(t *testing.T) {
+	reader := rand.Reader
+	rand.Reader = brokenEntropy{}
+
+	t.Cleanup(func() {
+		rand.Reader = reader
+	})
+}
+
+func testContext(request Request) *Context {
+	if request.Headers == nil {
+		request.Headers = make(map[string]string)
+	}
+
+	return &Context{Request: request, values: newValueStore()}
+}
+
+func testDispatch(app Banjo, request Request) *Context {
+	ctx := testContext(request)
+
+	app.dispatch(ctx)
+	ctx.prepareResponse()
+
+	return ctx
+}
@@ -0,0 +1,491 @@
+package banjo
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// contextWriter struct
+//
+// http.ResponseWriter implementation which
+// writes response of net/http handler to Context
+//
+type contextWriter struct {
+	ctx         *Context
+	header      http.Header
+	wroteHeader bool
+	streaming   bool
+}
+
+// ServeHTTP function
+//
+// Implements http.Handler, so banjo application
+// can run under http.Server or httptest.NewServer
+//
+// Params:
+// - w {http.ResponseWriter}
+// - r {*http.Request}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := Context{
+		Request:     requestFromHTTP(r),
+		Response:    Response{},
+		tlsState:    r.TLS,
+		httpWriter:  w,
+		httpRequest: r,
+		remote:      r.RemoteAddr,
+		proxies:     banjo.proxies,
+		values:      newValueStore(),
+	}
+
+	ctx.requestCtx, ctx.cancel = mergeCancel(r.Context(), banjo.baseContext())
+	defer ctx.cancel()
+
+	banjo.dispatch(&ctx)
+
+	if ctx.hijacked {
+		banjo.logRequest(&ctx)
+		return
+	}
+
+	if ctx.writer != nil && ctx.writer.Streaming() {
+		ctx.writer.close()
+		banjo.logRequest(&ctx)
+
+		if ctx.writer.failed {
+			panic(http.ErrAbortHandler)
+		}
+
+		return
+	}
+
+	ctx.prepareResponse()
+	banjo.logRequest(&ctx)
+
+	copyHTTPHeaders(w.Header(), ctx.Response)
+	w.Header().Set("Content-Length", strconv.Itoa(len(ctx.Response.Body)))
+	w.WriteHeader(ctx.Response.Status)
+	io.WriteString(w, ctx.Response.Body)
+}
+
+// Mount function
+//
+// Mounts net/http handler for all methods under given prefix,
+// prefix is stripped from URL before passing request to handler
+// Example usage:
+// app.Mount("/debug/pprof", http.DefaultServeMux)
+//
+// Params:
+// - prefix  {string} URL prefix
+// - handler {http.Handler}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) Mount(prefix string, handler http.Handler) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	banjo.routes.Mount(prefix, WrapHandler(http.StripPrefix(prefix, handler)))
+}
+
+// WrapHandler function
+//
+// Converts net/http handler to banjo closure
+//
+// Params:
+// - handler {http.Handler}
+//
+// Response:
+// - closure {func(ctx *Context)}
+//
+func WrapHandler(handler http.Handler) func(ctx *Context) {
+	return func(ctx *Context) {
+		w := &contextWriter{ctx: ctx, header: http.Header{}}
+
+		handler.ServeHTTP(w, ctx.HTTPRequest())
+
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+}
+
+// WrapMiddleware function
+//
+// Converts standard func(http.Handler) http.Handler
+// middleware to banjo Middleware,
+// changes of request headers are passed to the next closure
+// Example usage:
+// app.Use(banjo.WrapMiddleware(handlers.RecoveryHandler()))
+//
+// Params:
+// - middleware {func(http.Handler) http.Handler}
+//
+// Response:
+// - middleware {Middleware}
+//
+func WrapMiddleware(middleware func(http.Handler) http.Handler) Middleware {
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			outer := &contextWriter{ctx: ctx, header: http.Header{}}
+
+			inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx.updateFromHTTP(r)
+
+				if w == outer && !outer.wroteHeader {
+					outer.wroteHeader = true
+					outer.copyHeader()
+					next(ctx)
+					return
+				}
+
+				next(ctx)
+
+				if ctx.writer != nil && ctx.writer.Streaming() {
+					return
+				}
+
+				response := ctx.Response
+				ctx.Response = Response{}
+
+				copyHTTPHeaders(w.Header(), response)
+				if response.Status == 0 {
+					response.Status = http.StatusOK
+				}
+
+				w.WriteHeader(response.Status)
+				io.WriteString(w, response.Body)
+			})
+
+			middleware(inner).ServeHTTP(outer, ctx.HTTPRequest())
+
+			if !outer.wroteHeader {
+				outer.WriteHeader(http.StatusOK)
+			}
+		}
+	}
+}
+
+// HTTPRequest function
+//
+// Returns *http.Request built from ctx.Request
+//
+// Params:
+// - None
+//
+// Response:
+// - request {*http.Request}
+//
+func (ctx *Context) HTTPRequest() *http.Request {
+	request, err := http.NewRequest(ctx.Request.Method, ctx.Request.URL, strings.NewReader(ctx.Request.Params))
+	if err != nil {
+		request, _ = http.NewRequest("GET", "/", strings.NewReader(ctx.Request.Params))
+		request.Method = ctx.Request.Method
+	}
+
+	for k, v := range ctx.Request.Headers {
+		request.Header.Set(k, v)
+	}
+
+	request.Host = ctx.Request.Header("Host")
+	request.RequestURI = ctx.Request.URL
+	request.TLS = ctx.tlsState
+
+	if proto := ctx.Request.HTTPVersion; proto != "" {
+		if major, minor, ok := http.ParseHTTPVersion(proto); ok {
+			request.Proto, request.ProtoMajor, request.ProtoMinor = proto, major, minor
+		}
+	}
+
+	request.RemoteAddr = ctx.RemoteAddr()
+
+	return request.WithContext(ctx.Context())
+}
+
+// hijackHTTP function
+//
+// Takes over connection from http.Server
+// for protocols like WebSocket
+//
+// Params:
+// - None
+//
+// Response:
+// - ok {bool} true if connection was taken over
+//
+func (ctx *Context) hijackHTTP() bool {
+	hijacker, ok := ctx.httpWriter.(http.Hijacker)
+	if !ok {
+		return false
+	}
+
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return false
+	}
+
+	ctx.conn = conn
+
+	return true
+}
+
+// updateFromHTTP function
+//
+// Applies changes made by net/http middleware to ctx.Request
+//
+// Params:
+// - r {*http.Request}
+//
+// Response:
+// - None
+//
+func (ctx *Context) updateFromHTTP(r *http.Request) {
+	ctx.Request.Method = r.Method
+	ctx.Request.URL = r.URL.RequestURI()
+	ctx.Request.Headers = headersFromHTTP(r.Header)
+
+	if r.Host != "" {
+		ctx.Request.Headers["Host"] = r.Host
+	}
+}
+
+// Header function
+//
+// Implements http.ResponseWriter
+//
+// Params:
+// - None
+//
+// Response:
+// - header {http.Header}
+//
+func (w *contextWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader function
+//
+// Implements http.ResponseWriter,
+// copies status & headers to ctx.Response
+//
+// Params:
+// - status {int}
+//
+// Response:
+// - None
+//
+func (w *contextWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.copyHeader()
+	w.ctx.Response.Status = status
+}
+
+// copyHeader function
+//
+// Copies headers set by net/http handler to ctx.Response
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (w *contextWriter) copyHeader() {
+	if w.ctx.Response.Headers == nil {
+		w.ctx.Response.Headers = make(map[string]string)
+	}
+
+	for k, v := range headersFromHTTP(w.header) {
+		if k != "Set-Cookie" {
+			w.ctx.Response.Headers[k] = v
+		}
+	}
+
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": w.header["Set-Cookie"]}}).Cookies()
+	w.ctx.Response.Cookies = append(w.ctx.Response.Cookies, cookies...)
+}
+
+// Write function
+//
+// Implements http.ResponseWriter,
+// appends data to ctx.Response.Body until Flush is called
+//
+// Params:
+// - data {[]byte}
+//
+// Response:
+// - n   {int}
+// - err {error}
+//
+func (w *contextWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.streaming {
+		return w.ctx.Writer().Write(data)
+	}
+
+	w.ctx.Response.Body += string(data)
+
+	return len(data), nil
+}
+
+// Flush function
+//
+// Implements http.Flusher, switches response to streaming
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (w *contextWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.streaming {
+		w.streaming = true
+		body := w.ctx.Response.Body
+		w.ctx.Response.Body = ""
+		w.ctx.Writer().Write([]byte(body))
+	}
+
+	w.ctx.Writer().Flush()
+}
+
+// requestFromHTTP function
+//
+// Converts *http.Request to banjo Request
+//
+// Params:
+// - r {*http.Request}
+//
+// Response:
+// - request {Request}
+//
+func requestFromHTTP(r *http.Request) Request {
+	var raw []byte
+
+	if r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			raw = data
+		}
+	}
+
+	body := string(raw)
+
+	headers := headersFromHTTP(r.Header)
+	if r.Host != "" {
+		headers["Host"] = r.Host
+	}
+
+	url := r.RequestURI
+	if url == "" {
+		url = r.URL.RequestURI()
+	}
+
+	params, files := make(map[string]string), []map[string]string{}
+
+	if !encodedBody(headers) {
+		params, files = parseParams(body, headers["Content-Type"])
+	}
+
+	return Request{
+		Headers:     headers,
+		Params:      body,
+		Body:        raw,
+		Files:       files,
+		MapParams:   params,
+		Method:      r.Method,
+		URL:         url,
+		HTTPVersion: r.Proto,
+	}
+}
+
+// headersFromHTTP function
+//
+// Converts http.Header to map[string]string,
+// repeated headers are joined same way as in Parser
+//
+// Params:
+// - header {http.Header}
+//
+// Response:
+// - headers {map[string]string}
+//
+func headersFromHTTP(header http.Header) map[string]string {
+	headers := make(map[string]string)
+
+	for k, v := range header {
+		headers[k] = strings.Join(v, "; ")
+	}
+
+	return headers
+}
+
+// copyHTTPHeaders function
+//
+// Copies banjo headers & cookies to http.Header skipping
+// connection management headers handled by net/http
+//
+// Params:
+// - target   {http.Header}
+// - response {Response}
+//
+// Response:
+// - None
+//
+func copyHTTPHeaders(target http.Header, response Response) {
+	for k, v := range response.Headers {
+		if k == "Connection" || k == "Transfer-Encoding" {
+			continue
+		}
+
+		target.Set(k, v)
+	}
+
+	for _, cookie := range response.Cookies {
+		target.Add("Set-Cookie", cookie.String())
+	}
+}
+
+// mergeCancel function
+//
+// Returns context derived from parent,
+// which is also cancelled when other is done
+//
+// Params:
+// - parent {context.Context}
+// - other  {context.Context}
+//
+// Response:
+// - ctx    {context.Context}
+// - cancel {context.CancelFunc}
+//
+func mergeCancel(parent context.Context, other context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	go func() {
+		select {
+		case <-other.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
@@ -0,0 +1,141 @@
+package banjo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBanjoServeHTTPUnderHTTPTestServer(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/foo", func(ctx *Context) {
+		ctx.Response.Status = 201
+		ctx.JSON(M{"foo": ctx.Request.MapParams["foo"]})
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/foo", "application/x-www-form-urlencoded", strings.NewReader("foo=bar"))
+	if err != nil {
+		t.Fatalf("Request should succeed")
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != 201 {
+		t.Errorf("Status should be 201")
+	}
+
+	if resp.Header.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type should be application/json")
+	}
+
+	if string(body) != "{\"foo\":\"bar\"}" {
+		t.Errorf("Body should be {\"foo\":\"bar\"}")
+	}
+}
+
+func TestBanjoServeHTTPStreaming(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Get("/export", func(ctx *Context) {
+		ctx.Stream("text/csv", strings.NewReader("id,name\n"))
+	})
+
+	recorder := httptest.NewRecorder()
+	app.ServeHTTP(recorder, httptest.NewRequest("GET", "/export", nil))
+
+	if recorder.Body.String() != "id,name\n" || !recorder.Flushed {
+		t.Errorf("Body should be streamed")
+	}
+
+	if recorder.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("Content-Type should be text/csv")
+	}
+}
+
+func TestMountStripsPrefix(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Mount("/api/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		w.WriteHeader(202)
+		w.Write([]byte(r.Method))
+	}))
+
+	ctx := testContext(Request{Method: "DELETE", URL: "/api/users/1", Headers: map[string]string{}})
+	app.dispatch(ctx)
+
+	if ctx.Response.Status != 202 || ctx.Response.Body != "DELETE" {
+		t.Errorf("Mounted handler should handle any method")
+	}
+
+	if ctx.Response.Headers["X-Path"] != "/users/1" {
+		t.Errorf("Prefix should be stripped")
+	}
+}
+
+func TestWrapMiddlewareShortCircuit(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Token") == "" {
+				http.Error(w, "denied", http.StatusUnauthorized)
+				return
+			}
+			r.Header.Set("X-User", "foo")
+			w.Header().Set("X-Request-ID", "1")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	app := Create(DefaultConfig())
+	app.Use(WrapMiddleware(deny))
+	app.Get("/me", func(ctx *Context) {
+		ctx.HTML(ctx.Request.Headers["X-User"])
+	})
+
+	ctx := testContext(Request{Method: "GET", URL: "/me", Headers: map[string]string{}})
+	app.dispatch(ctx)
+
+	if ctx.Response.Status != 401 || ctx.Response.Body != "denied\n" {
+		t.Errorf("Middleware should stop request")
+	}
+
+	ctx = testContext(Request{Method: "GET", URL: "/me", Headers: map[string]string{"X-Token": "secret"}})
+	app.dispatch(ctx)
+
+	if ctx.Response.Status != 200 || ctx.Response.Body != "foo" {
+		t.Errorf("Request header changes should be passed to closure")
+	}
+
+	if ctx.Response.Headers["X-Request-Id"] != "1" {
+		t.Errorf("Response headers set by middleware should be kept")
+	}
+}
+
+func TestWrapMiddlewareWrappedWriter(t *testing.T) {
+	tag := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := httptest.NewRecorder()
+			next.ServeHTTP(recorder, r)
+
+			w.Header().Set("X-Tagged", "yes")
+			w.WriteHeader(recorder.Code)
+			w.Write([]byte("[" + recorder.Body.String() + "]"))
+		})
+	}
+
+	app := Create(DefaultConfig())
+	app.Get("/foo", Chain(func(ctx *Context) {
+		ctx.Response.Status = 201
+		ctx.HTML("foo")
+	}, WrapMiddleware(tag)))
+
+	ctx := testContext(Request{Method: "GET", URL: "/foo", Headers: map[string]string{}})
+	app.dispatch(ctx)
+
+	if ctx.Response.Status != 201 || ctx.Response.Body != "[foo]" || ctx.Response.Headers["X-Tagged"] != "yes" {
+		t.Errorf("Response should be passed through wrapped writer")
+	}
+}
@@ -0,0 +1,132 @@
+package banjo
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// IPFilter struct
+//
+// Allow & deny lists of IPv4 & IPv6 networks,
+// lists can be replaced at runtime by Update
+//
+type IPFilter struct {
+	mutex  sync.RWMutex
+	allow  []*net.IPNet
+	deny   []*net.IPNet
+	logger Logger
+}
+
+// NewIPFilter function
+//
+// Returns filter for given IPs & CIDRs,
+// deny list has priority, all IPs not denied
+// are allowed if allow list is empty
+//
+// Params:
+// - allow {[]string} allowed IPs & CIDRs
+// - deny  {[]string} denied IPs & CIDRs
+//
+// Response:
+// - filter {*IPFilter}
+// - err    {error} invalid entry
+//
+func NewIPFilter(allow []string, deny []string) (*IPFilter, error) {
+	filter := &IPFilter{logger: CreateLogger()}
+
+	if err := filter.Update(allow, deny); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// Update function
+//
+// Replaces filter lists, current lists are kept
+// if any of new entries is invalid
+//
+// Params:
+// - allow {[]string} allowed IPs & CIDRs
+// - deny  {[]string} denied IPs & CIDRs
+//
+// Response:
+// - err {error} invalid entry
+//
+func (filter *IPFilter) Update(allow []string, deny []string) error {
+	allowed, err := parseNetworks(allow)
+	if err != nil {
+		return err
+	}
+
+	denied, err := parseNetworks(deny)
+	if err != nil {
+		return err
+	}
+
+	filter.mutex.Lock()
+	filter.allow, filter.deny = allowed, denied
+	filter.mutex.Unlock()
+
+	return nil
+}
+
+// Allowed function
+//
+// Checks IP against filter lists
+//
+// Params:
+// - ip {string}
+//
+// Response:
+// - ok {bool} false for invalid IP
+//
+func (filter *IPFilter) Allowed(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	filter.mutex.RLock()
+	defer filter.mutex.RUnlock()
+
+	if containsIP(filter.deny, parsed) {
+		return false
+	}
+
+	return len(filter.allow) == 0 || containsIP(filter.allow, parsed)
+}
+
+// Middleware function
+//
+// Returns middleware answering 403 for requests
+// from blocked client IPs, ctx.ClientIP() is checked
+// Example usage:
+// office, err := banjo.NewIPFilter([]string{"10.0.0.0/8", "fd00::/8"}, nil)
+// app.Get("/metrics", banjo.Chain(metrics, office.Middleware()))
+//
+// Params:
+// - None
+//
+// Response:
+// - middleware {Middleware}
+//
+func (filter *IPFilter) Middleware() Middleware {
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			ip := ctx.ClientIP()
+
+			if !filter.Allowed(ip) {
+				filter.logger.Warning(fmt.Sprintf("Request from %s to %s blocked by IP filter", ip, ctx.Request.URL))
+
+				ctx.Response.Status = http.StatusForbidden
+				ctx.Response.Body = http.StatusText(http.StatusForbidden)
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
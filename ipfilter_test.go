@@ -0,0 +1,65 @@
+package banjo
+
+import "testing"
+
+func TestIPFilterLists(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.0.0.0/8", "fd00::/8"}, []string{"10.0.0.66"})
+	if err != nil {
+		t.Fatalf("Filter should be created")
+	}
+
+	cases := map[string]bool{
+		"10.1.2.3":   true,
+		"10.0.0.66":  false,
+		"fd00::1":    true,
+		"8.8.8.8":    false,
+		"2001:db8::": false,
+		"foo":        false,
+	}
+
+	for ip, allowed := range cases {
+		if filter.Allowed(ip) != allowed {
+			t.Errorf("%s should be allowed: %v", ip, allowed)
+		}
+	}
+}
+
+func TestIPFilterUpdate(t *testing.T) {
+	filter, _ := NewIPFilter(nil, []string{"1.2.3.4"})
+
+	if filter.Allowed("1.2.3.4") || !filter.Allowed("5.6.7.8") {
+		t.Errorf("Deny list should be applied")
+	}
+
+	if filter.Update([]string{"foo"}, nil) == nil || filter.Allowed("1.2.3.4") {
+		t.Errorf("Invalid update should keep current lists")
+	}
+
+	filter.Update([]string{"1.2.3.0/24"}, nil)
+
+	if !filter.Allowed("1.2.3.4") || filter.Allowed("5.6.7.8") {
+		t.Errorf("New lists should be applied")
+	}
+}
+
+func TestIPFilterMiddleware(t *testing.T) {
+	filter, _ := NewIPFilter([]string{"127.0.0.1"}, nil)
+
+	action := Chain(func(ctx *Context) {
+		ctx.HTML("ok")
+	}, filter.Middleware())
+
+	ctx := &Context{remote: "127.0.0.1:4000"}
+	action(ctx)
+
+	if ctx.Response.Body != "ok" {
+		t.Errorf("Allowed IP should pass")
+	}
+
+	ctx = &Context{remote: "192.168.0.1:4000"}
+	action(ctx)
+
+	if ctx.Response.Status != 403 {
+		t.Errorf("Blocked IP should get 403")
+	}
+}
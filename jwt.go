@@ -0,0 +1,603 @@
+package banjo
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JWTClaimsKey is Context store key of verified JWT claims
+const JWTClaimsKey = "banjo.jwt_claims"
+
+// JWTOptions struct
+//
+// JWT verification configuration, algorithm is chosen
+// by key type: []byte for HS256, *rsa.PublicKey for RS256,
+// *ecdsa.PublicKey for ES256 & ed25519.PublicKey for EdDSA
+//
+type JWTOptions struct {
+	// Key verifies tokens without kid header
+	Key interface{}
+
+	// KeySet verifies tokens by kid header
+	KeySet KeySet
+
+	// Issuer is required iss claim, not checked if empty
+	Issuer string
+
+	// Audience is required aud claim value, not checked if empty
+	Audience string
+
+	// ClockSkew is tolerance for exp & nbf checks
+	ClockSkew time.Duration
+
+	// Realm is sent in WWW-Authenticate challenge
+	Realm string
+}
+
+// KeySet type is map[string]interface{} alias
+//
+// Verification keys by key id
+//
+type KeySet map[string]interface{}
+
+// Claims struct
+//
+// Registered JWT claims, other claims are
+// available by DecodeClaims
+//
+type Claims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+
+	raw []byte
+}
+
+// Audience type is []string alias
+//
+// aud claim which can be string or array of strings
+//
+type Audience []string
+
+// NumericDate struct
+//
+// JWT time in seconds since epoch
+//
+type NumericDate struct {
+	time.Time
+}
+
+// jwtHeader struct
+//
+// JOSE header fields used for verification
+//
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// jwk struct
+//
+// JSON Web Key fields of supported key types
+//
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+	K       string `json:"k"`
+}
+
+// JWT function
+//
+// Returns middleware verifying Bearer JWT, verified claims
+// are stored on Context, 401 with RFC 6750 challenge is sent
+// for missing or invalid tokens, verification error is logged
+// Example usage:
+// keys, err := banjo.LoadJWKS("jwks.json")
+// app.Use(banjo.JWT(banjo.JWTOptions{KeySet: keys, Issuer: "https://id.example.com"}))
+//
+// Params:
+// - options {JWTOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func JWT(options JWTOptions) Middleware {
+	auth := BearerAuth(options.Realm, func(token string) (interface{}, error) {
+		return VerifyJWT(token, options)
+	})
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return auth(func(ctx *Context) {
+			ctx.Set(JWTClaimsKey, ctx.Principal())
+			next(ctx)
+		})
+	}
+}
+
+// JWTClaims function
+//
+// Returns claims verified by JWT middleware
+//
+// Params:
+// - None
+//
+// Response:
+// - claims {*Claims} nil if request wasn't authenticated by JWT
+//
+func (ctx *Context) JWTClaims() *Claims {
+	claims, _ := GetAs[*Claims](ctx, JWTClaimsKey)
+
+	return claims
+}
+
+// DecodeClaims function
+//
+// Decodes verified JWT payload to custom claims type
+// Example usage:
+// claims, err := banjo.DecodeClaims[MyClaims](ctx)
+//
+// Params:
+// - ctx {*Context}
+//
+// Response:
+// - claims {T}
+// - err    {error}
+//
+func DecodeClaims[T any](ctx *Context) (T, error) {
+	var claims T
+
+	verified := ctx.JWTClaims()
+	if verified == nil {
+		return claims, errors.New("request has no verified JWT")
+	}
+
+	err := json.Unmarshal(verified.raw, &claims)
+
+	return claims, err
+}
+
+// VerifyJWT function
+//
+// Verifies JWS compact token signature & registered claims
+//
+// Params:
+// - token   {string}
+// - options {JWTOptions}
+//
+// Response:
+// - claims {*Claims}
+// - err    {error} description of verification failure
+//
+func VerifyJWT(token string, options JWTOptions) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errors.New("malformed token header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed token signature")
+	}
+
+	key, err := options.key(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Algorithm, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed token payload")
+	}
+
+	claims := &Claims{raw: payload}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+
+	return claims, options.validate(claims, time.Now())
+}
+
+// LoadJWKS function
+//
+// Loads key set from JSON Web Key Set file
+//
+// Params:
+// - path {string}
+//
+// Response:
+// - keys {KeySet}
+// - err  {error}
+//
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJWKS(data)
+}
+
+// ParseJWKS function
+//
+// Parses JSON Web Key Set with RSA, EC P-256,
+// Ed25519 & symmetric keys
+//
+// Params:
+// - data {[]byte} JWKS JSON
+//
+// Response:
+// - keys {KeySet}
+// - err  {error}
+//
+func ParseJWKS(data []byte) (KeySet, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(KeySet)
+
+	for _, key := range set.Keys {
+		parsed, err := key.parse()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %v", key.KeyID, err)
+		}
+
+		keys[key.KeyID] = parsed
+	}
+
+	return keys, nil
+}
+
+// UnmarshalJSON function
+//
+// Accepts aud claim as string or array
+//
+// Params:
+// - data {[]byte}
+//
+// Response:
+// - err {error}
+//
+func (audience *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*audience = Audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*audience = list
+
+	return nil
+}
+
+// UnmarshalJSON function
+//
+// Parses seconds since epoch, fractions are allowed
+//
+// Params:
+// - data {[]byte}
+//
+// Response:
+// - err {error}
+//
+func (date *NumericDate) UnmarshalJSON(data []byte) error {
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+
+	date.Time = time.Unix(0, int64(seconds*float64(time.Second)))
+
+	return nil
+}
+
+// MarshalJSON function
+//
+// Returns seconds since epoch
+//
+// Params:
+// - None
+//
+// Response:
+// - data {[]byte}
+// - err  {error}
+//
+func (date NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(date.Unix(), 10)), nil
+}
+
+// key function
+//
+// Returns verification key for kid header
+//
+// Params:
+// - kid {string}
+//
+// Response:
+// - key {interface{}}
+// - err {error}
+//
+func (options JWTOptions) key(kid string) (interface{}, error) {
+	if kid != "" && options.KeySet != nil {
+		if key, ok := options.KeySet[kid]; ok {
+			return key, nil
+		}
+
+		return nil, errors.New("unknown key id")
+	}
+
+	if options.Key != nil {
+		return options.Key, nil
+	}
+
+	if len(options.KeySet) == 1 {
+		for _, key := range options.KeySet {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("unknown key id")
+}
+
+// validate function
+//
+// Checks exp, nbf, iss & aud claims
+//
+// Params:
+// - claims {*Claims}
+// - now    {time.Time}
+//
+// Response:
+// - err {error}
+//
+func (options JWTOptions) validate(claims *Claims, now time.Time) error {
+	if claims.ExpiresAt != nil && now.After(claims.ExpiresAt.Add(options.ClockSkew)) {
+		return errors.New("token is expired")
+	}
+
+	if claims.NotBefore != nil && now.Add(options.ClockSkew).Before(claims.NotBefore.Time) {
+		return errors.New("token is not valid yet")
+	}
+
+	if options.Issuer != "" && claims.Issuer != options.Issuer {
+		return errors.New("invalid issuer")
+	}
+
+	if options.Audience != "" && !containsString(claims.Audience, options.Audience) {
+		return errors.New("invalid audience")
+	}
+
+	return nil
+}
+
+// parse function
+//
+// Converts JSON Web Key to verification key
+//
+// Params:
+// - None
+//
+// Response:
+// - key {interface{}}
+// - err {error}
+//
+func (key jwk) parse() (interface{}, error) {
+	switch key.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(key.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(key.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if key.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", key.Curve)
+		}
+
+		x, err := decodeBigInt(key.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(key.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || key.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(key.K)
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", key.KeyType)
+}
+
+// verifySignature function
+//
+// Verifies signature with algorithm matching key type
+//
+// Params:
+// - algorithm {string} alg header
+// - key       {interface{}}
+// - input     {string} signed part of token
+// - signature {[]byte}
+//
+// Response:
+// - err {error}
+//
+func verifySignature(algorithm string, key interface{}, input string, signature []byte) error {
+	invalid := errors.New("invalid signature")
+	hash := sha256.Sum256([]byte(input))
+
+	switch key := key.(type) {
+	case []byte:
+		if algorithm != "HS256" {
+			break
+		}
+
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(input))
+
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return invalid
+		}
+
+		return nil
+	case *rsa.PublicKey:
+		if algorithm != "RS256" {
+			break
+		}
+
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) != nil {
+			return invalid
+		}
+
+		return nil
+	case *ecdsa.PublicKey:
+		if algorithm != "ES256" || key.Curve != elliptic.P256() {
+			break
+		}
+
+		if len(signature) != 64 {
+			return invalid
+		}
+
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key, hash[:], r, s) {
+			return invalid
+		}
+
+		return nil
+	case ed25519.PublicKey:
+		if algorithm != "EdDSA" {
+			break
+		}
+
+		if !ed25519.Verify(key, []byte(input), signature) {
+			return invalid
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unexpected algorithm %s", algorithm)
+}
+
+// decodeSegment function
+//
+// Decodes base64url JSON token segment
+//
+// Params:
+// - segment {string}
+// - target  {interface{}}
+//
+// Response:
+// - err {error}
+//
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+// decodeBigInt function
+//
+// Decodes base64url big-endian integer
+//
+// Params:
+// - value {string}
+//
+// Response:
+// - n   {*big.Int}
+// - err {error}
+//
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// containsString function
+//
+// Checks if list contains value
+//
+// Params:
+// - list  {[]string}
+// - value {string}
+//
+// Response:
+// - ok {bool}
+//
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
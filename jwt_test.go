@@ -0,0 +1,200 @@
+package banjo
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signJWT(t *testing.T, algorithm string, kid string, key interface{}, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(input))
+
+	var signature []byte
+
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	case *ecdsa.PrivateKey:
+		r, s, _ := ecdsa.Sign(rand.Reader, key, hash[:])
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(input))
+	default:
+		t.Fatalf("Unsupported key")
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":  "https://id.example.com",
+		"sub":  "user-1",
+		"aud":  []string{"api", "web"},
+		"exp":  time.Now().Add(time.Minute).Unix(),
+		"role": "admin",
+	}
+}
+
+func TestVerifyJWTAlgorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	secret := []byte("secret")
+
+	cases := []struct {
+		algorithm string
+		private   interface{}
+		public    interface{}
+	}{
+		{"HS256", secret, secret},
+		{"RS256", rsaKey, &rsaKey.PublicKey},
+		{"ES256", ecKey, &ecKey.PublicKey},
+		{"EdDSA", edPrivate, edPublic},
+	}
+
+	for _, c := range cases {
+		token := signJWT(t, c.algorithm, "", c.private, validClaims())
+		options := JWTOptions{Key: c.public, Issuer: "https://id.example.com", Audience: "api"}
+
+		claims, err := VerifyJWT(token, options)
+		if err != nil || claims.Subject != "user-1" {
+			t.Errorf("%s token should be verified: %v", c.algorithm, err)
+		}
+
+		tampered := token[:len(token)-4] + "AAAA"
+		if _, err := VerifyJWT(tampered, options); err == nil {
+			t.Errorf("%s token with invalid signature should be rejected", c.algorithm)
+		}
+	}
+}
+
+func TestVerifyJWTRejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	token := signJWT(t, "HS256", "", []byte("public"), validClaims())
+
+	if _, err := VerifyJWT(token, JWTOptions{Key: &rsaKey.PublicKey}); err == nil || !strings.Contains(err.Error(), "algorithm") {
+		t.Errorf("Algorithm should match key type")
+	}
+}
+
+func TestVerifyJWTClaims(t *testing.T) {
+	secret := []byte("secret")
+	options := JWTOptions{Key: secret, Issuer: "https://id.example.com", Audience: "api", ClockSkew: 30 * time.Second}
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+	if _, err := VerifyJWT(signJWT(t, "HS256", "", secret, claims), options); err != nil {
+		t.Errorf("Token expired within clock skew should be accepted")
+	}
+
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	if _, err := VerifyJWT(signJWT(t, "HS256", "", secret, claims), options); err == nil || err.Error() != "token is expired" {
+		t.Errorf("Expired token should be rejected")
+	}
+
+	claims = validClaims()
+	claims["nbf"] = time.Now().Add(time.Minute).Unix()
+	if _, err := VerifyJWT(signJWT(t, "HS256", "", secret, claims), options); err == nil {
+		t.Errorf("Token used before nbf should be rejected")
+	}
+
+	claims = validClaims()
+	claims["aud"] = "other"
+	if _, err := VerifyJWT(signJWT(t, "HS256", "", secret, claims), options); err == nil || err.Error() != "invalid audience" {
+		t.Errorf("Token for other audience should be rejected")
+	}
+
+	claims = validClaims()
+	claims["iss"] = "https://evil.com"
+	if _, err := VerifyJWT(signJWT(t, "HS256", "", secret, claims), options); err == nil {
+		t.Errorf("Token from other issuer should be rejected")
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	encode := base64.RawURLEncoding.EncodeToString
+
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encode(ecKey.X.Bytes()), "y": encode(ecKey.Y.Bytes())},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": encode(edPublic)},
+		{"kty": "oct", "kid": "hs", "k": encode([]byte("secret"))},
+	}})
+
+	keys, err := ParseJWKS(jwks)
+	if err != nil || len(keys) != 4 {
+		t.Fatalf("Key set should be parsed: %v", err)
+	}
+
+	tokens := []string{
+		signJWT(t, "RS256", "rsa", rsaKey, validClaims()),
+		signJWT(t, "ES256", "ec", ecKey, validClaims()),
+		signJWT(t, "EdDSA", "ed", edPrivate, validClaims()),
+		signJWT(t, "HS256", "hs", []byte("secret"), validClaims()),
+	}
+
+	for _, token := range tokens {
+		if _, err := VerifyJWT(token, JWTOptions{KeySet: keys}); err != nil {
+			t.Errorf("Token should be verified by kid: %v", err)
+		}
+	}
+
+	if _, err := VerifyJWT(signJWT(t, "HS256", "missing", []byte("secret"), validClaims()), JWTOptions{KeySet: keys}); err == nil {
+		t.Errorf("Unknown kid should be rejected")
+	}
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	type appClaims struct {
+		Role string `json:"role"`
+	}
+
+	secret := []byte("secret")
+	app := Create(DefaultConfig())
+	app.Get("/me", Chain(func(ctx *Context) {
+		claims, err := DecodeClaims[appClaims](ctx)
+		if err == nil && ctx.JWTClaims().Subject == "user-1" {
+			ctx.HTML(claims.Role)
+		}
+	}, JWT(JWTOptions{Key: secret, Realm: "api"})))
+
+	token := signJWT(t, "HS256", "", secret, validClaims())
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/me", Headers: map[string]string{"Authorization": "Bearer " + token}})
+
+	if ctx.Response.Body != "admin" {
+		t.Errorf("Claims should be available in closure")
+	}
+
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/me", Headers: map[string]string{"Authorization": "Bearer " + signJWT(t, "HS256", "", secret, claims)}})
+
+	if ctx.Response.Status != 401 || !strings.Contains(ctx.Response.Headers["WWW-Authenticate"], `error="invalid_token"`) {
+		t.Errorf("Expired token should be rejected with RFC 6750 error")
+	}
+}
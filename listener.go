@@ -0,0 +1,151 @@
+package banjo
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// UnixPrefix is Config.Address prefix for Unix domain sockets
+const UnixPrefix = "unix:"
+
+// listenFdsStart is first file descriptor passed by systemd
+const listenFdsStart = 3
+
+// maxAcceptDelay is upper limit of delay after temporary accept error
+const maxAcceptDelay = time.Second
+
+// listen function
+//
+// Creates listener from systemd socket activation
+// or from configured address
+//
+// Params:
+// - None
+//
+// Response:
+// - listener {net.Listener}
+// - err      {error}
+//
+func (banjo Banjo) listen() (net.Listener, error) {
+	listener, err := activationListener()
+	if listener != nil || err != nil {
+		return listener, err
+	}
+
+	network, address := banjo.config.network()
+
+	if network == "unix" {
+		if info, err := os.Stat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(address)
+		}
+	}
+
+	return net.Listen(network, address)
+}
+
+// network function
+//
+// Returns network & address for net.Listen
+//
+// Params:
+// - None
+//
+// Response:
+// - network {string} "tcp" or "unix"
+// - address {string}
+//
+func (config Config) network() (string, string) {
+	if strings.HasPrefix(config.Address, UnixPrefix) {
+		return "unix", strings.TrimPrefix(config.Address, UnixPrefix)
+	}
+
+	if config.Address != "" {
+		return "tcp", config.Address
+	}
+
+	return "tcp", config.host + ":" + config.port
+}
+
+// activationListener function
+//
+// Returns listener passed by systemd socket activation,
+// nil if process wasn't socket activated
+//
+// Params:
+// - None
+//
+// Response:
+// - listener {net.Listener}
+// - err      {error}
+//
+func activationListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, errors.New("LISTEN_FDS didn't contain file descriptors")
+	}
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	file := os.NewFile(uintptr(listenFdsStart), "LISTEN_FD_3")
+	defer file.Close()
+
+	return net.FileListener(file)
+}
+
+// retryableAccept function
+//
+// Reports whether accept error is transient, e.g. process
+// ran out of file descriptors or connection was aborted
+//
+// Params:
+// - err {error} Accept error
+//
+// Response:
+// - ok {bool}
+//
+func retryableAccept(err error) bool {
+	for _, errno := range []error{syscall.EMFILE, syscall.ENFILE, syscall.ENOBUFS, syscall.ENOMEM, syscall.ECONNABORTED, syscall.ECONNRESET} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// acceptDelay function
+//
+// Returns next delay after temporary accept error,
+// doubled each time up to maxAcceptDelay
+//
+// Params:
+// - delay {time.Duration} previous delay
+//
+// Response:
+// - delay {time.Duration}
+//
+func acceptDelay(delay time.Duration) time.Duration {
+	if delay == 0 {
+		return 5 * time.Millisecond
+	}
+
+	if delay *= 2; delay > maxAcceptDelay {
+		delay = maxAcceptDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,118 @@
+package banjo
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestServeOnEphemeralPort(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Get("/foo", func(ctx *Context) {
+		ctx.HTML("foo")
+	})
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	result := make(chan error, 1)
+	go func() { result <- app.Serve(listener) }()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Connection should be established")
+	}
+
+	conn.Write([]byte("GET /foo HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	data, _ := ioutil.ReadAll(conn)
+
+	if !strings.HasSuffix(string(data), "\r\n\r\nfoo") {
+		t.Errorf("Body should be `foo`")
+	}
+
+	listener.Close()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Errorf("Serve should return listener error")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Serve should return after listener is closed")
+	}
+}
+
+func TestRunOnUnixSocket(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "banjo")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "banjo.sock")
+	cnf := DefaultConfig()
+	cnf.Address = UnixPrefix + path
+	app := Create(cnf)
+	app.Get("/foo", func(ctx *Context) {
+		ctx.HTML("unix")
+	})
+
+	go app.Run()
+
+	var conn net.Conn
+	var err error
+	for i := 0; i < 50; i++ {
+		if conn, err = net.Dial("unix", path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err != nil {
+		t.Fatalf("Unix socket should accept connections")
+	}
+
+	conn.Write([]byte("GET /foo HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	data, _ := ioutil.ReadAll(conn)
+
+	if !strings.HasSuffix(string(data), "\r\n\r\nunix") {
+		t.Errorf("Body should be `unix`")
+	}
+}
+
+func TestRunReturnsListenError(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer listener.Close()
+
+	cnf := DefaultConfig()
+	cnf.Address = listener.Addr().String()
+
+	if err := Create(cnf).Run(); err == nil {
+		t.Errorf("Run should return error for busy address")
+	}
+}
+
+func TestActivationListenerIgnoresForeignPid(t *testing.T) {
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	os.Setenv("LISTEN_FDS", "1")
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+
+	listener, err := activationListener()
+	if listener != nil || err != nil {
+		t.Errorf("Listener should be used only by process with LISTEN_PID")
+	}
+}
+
+func TestRetryableAccept(t *testing.T) {
+	emfile := &net.OpError{Op: "accept", Net: "tcp", Err: os.NewSyscallError("accept", syscall.EMFILE)}
+
+	if !retryableAccept(emfile) {
+		t.Errorf("EMFILE should be retried")
+	}
+
+	if retryableAccept(net.ErrClosed) || retryableAccept(os.ErrPermission) {
+		t.Errorf("Closed listener & permission errors shouldn't be retried")
+	}
+}
@@ -0,0 +1,80 @@
+package banjo
+
+// Middleware type is func(next func(ctx *Context)) func(ctx *Context) alias
+//
+// Middleware wraps closure and can run code before
+// and after it or stop request processing by not calling next
+//
+type Middleware func(next func(ctx *Context)) func(ctx *Context)
+
+// Use function
+//
+// Adds middleware which runs for every request,
+// including requests without registered route,
+// first added middleware is the outermost one
+//
+// Params:
+// - middleware {...Middleware}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) Use(middleware ...Middleware) {
+	*banjo.middleware = append(*banjo.middleware, middleware...)
+}
+
+// Chain function
+//
+// Wraps closure with middleware for single route,
+// first middleware is the outermost one
+// Example usage:
+// app.Get("/admin", banjo.Chain(admin, auth, logging))
+//
+// Params:
+// - closure    {func(ctx *Context)} route closure
+// - middleware {...Middleware}
+//
+// Response:
+// - closure {func(ctx *Context)} wrapped closure
+//
+func Chain(closure func(ctx *Context), middleware ...Middleware) func(ctx *Context) {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		closure = middleware[i](closure)
+	}
+
+	return closure
+}
+
+// dispatch function
+//
+// Decompresses request body, finds closure for request
+// & runs it through middleware, OPTIONS requests for
+// registered urls without OPTIONS closure are answered
+// with Allow header
+//
+// Params:
+// - ctx {*Context}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) dispatch(ctx *Context) {
+	if err := banjo.decodeRequest(&ctx.Request); err != nil {
+		banjo.rejectBody(ctx, err)
+		return
+	}
+
+	action := banjo.routes.Block(ctx.Request.Method, ctx.Request.URL)
+
+	if _, ok := banjo.routes.OPTIONS[ctx.Request.URL]; !ok && ctx.Request.Method == "OPTIONS" {
+		if methods := banjo.routes.Methods(ctx.Request.URL); len(methods) > 0 {
+			action = allowMethods(methods)
+		}
+	}
+
+	if banjo.middleware != nil {
+		action = Chain(action, *banjo.middleware...)
+	}
+
+	action(ctx)
+}
@@ -0,0 +1,45 @@
+package banjo
+
+import "testing"
+
+func TestMiddlewareOrder(t *testing.T) {
+	order := ""
+	mark := func(name string) Middleware {
+		return func(next func(ctx *Context)) func(ctx *Context) {
+			return func(ctx *Context) {
+				order += name
+				next(ctx)
+			}
+		}
+	}
+
+	app := Create(DefaultConfig())
+	app.Use(mark("a"), mark("b"))
+	app.Get("/foo", Chain(func(ctx *Context) {
+		order += "!"
+	}, mark("c")))
+
+	app.dispatch(testContext(Request{Method: "GET", URL: "/foo"}))
+
+	if order != "abc!" {
+		t.Errorf("Middleware should run in order they were added")
+	}
+}
+
+func TestMiddlewareRunsForNotFound(t *testing.T) {
+	called := false
+	app := Create(DefaultConfig())
+	app.Use(func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			called = true
+			next(ctx)
+		}
+	})
+
+	ctx := testContext(Request{Method: "GET", URL: "/missing"})
+	app.dispatch(ctx)
+
+	if !called || ctx.Response.Status != 404 {
+		t.Errorf("Middleware should run for unknown routes")
+	}
+}
@@ -55,11 +55,17 @@ func (p Parser) Request(rawData string) Request {
 	}
 
 	headers := parseHeaders(arrH)
-	params, files := parseParams(rawB, headers["Content-Type"])
+	params, files := make(map[string]string), []map[string]string{}
+
+	// encoded body is parsed after decompression
+	if !encodedBody(headers) {
+		params, files = parseParams(rawB, headers["Content-Type"])
+	}
 
 	return Request{
 		Headers:     headers,
 		Params:      rawB,
+		Body:        []byte(rawB),
 		Files:       files,
 		MapParams:   params,
 		Method:      method,
@@ -80,6 +86,24 @@ func (p Parser) Request(rawData string) Request {
 func (p Parser) Response(data Response) string {
 	var buffer bytes.Buffer
 
+	buffer.WriteString(p.Head(data))
+	buffer.WriteString(data.Body)
+
+	return buffer.String()
+}
+
+// Head prepared status line & headers of banjo.Response
+// struct to Raw HTTP Response string without body
+//
+// Params:
+// - data {banjo.Response} prepared banjo.Response struct
+//
+// Response:
+// - response {string} Raw HTTP Response head string
+//
+func (p Parser) Head(data Response) string {
+	var buffer bytes.Buffer
+
 	buffer.WriteString(fmt.Sprintf("%s %d\r\n", HTTPVersion, data.Status))
 
 	for k, v := range data.Headers {
@@ -87,8 +111,14 @@ func (p Parser) Response(data Response) string {
 		buffer.WriteString(Separator)
 	}
 
+	for _, cookie := range data.Cookies {
+		if value := cookie.String(); value != "" {
+			buffer.WriteString("Set-Cookie: " + value)
+			buffer.WriteString(Separator)
+		}
+	}
+
 	buffer.WriteString(Separator)
-	buffer.WriteString(data.Body)
 
 	return buffer.String()
 }
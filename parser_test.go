@@ -28,6 +28,16 @@ func TestHTTPRequestJSONParamsParsing(t *testing.T) {
 	}
 }
 
+func TestHTTPRequestRawBodyIsPreserved(t *testing.T) {
+	p := Parser{}
+	rawRequest := "POST /foo HTTP/1.1\r\nContent-Type: text/plain\r\n\r\nfoo\r\n\r\nbar\x00"
+	request := p.Request(rawRequest)
+
+	if string(request.Body) != "foo\r\n\r\nbar\x00" {
+		t.Errorf("Raw body bytes should be preserved")
+	}
+}
+
 func TestHTTPRequestFormDataParamsParsing(t *testing.T) {
 	p := Parser{}
 	rawRequest := "POST /foo HTTP/1.1\r\nContent-Type: application/x-www-form-urlencoded\r\n\r\nfoo=bar&bar=foo"
@@ -74,3 +84,17 @@ func TestHTTPResponseJSONBodyParsing(t *testing.T) {
 		t.Errorf("Requests should be same")
 	}
 }
+
+func TestHTTPResponseHeadParsing(t *testing.T) {
+	p := Parser{}
+	str := "HTTP/1.1 200\r\nContent-Type: text/csv\r\n\r\n"
+	rawHead := p.Head(Response{
+		Headers: map[string]string{"Content-Type": "text/csv"},
+		Status:  200,
+		Body:    "id,name",
+	})
+
+	if str != rawHead {
+		t.Errorf("Head should not contain body")
+	}
+}
@@ -0,0 +1,228 @@
+package banjo
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRetryAfter is default value of Retry-After
+// header sent when server is saturated
+const DefaultRetryAfter = time.Second
+
+// rejectDrainTimeout is time allowed for client to finish
+// sending request after 503 response was written
+const rejectDrainTimeout = 500 * time.Millisecond
+
+// ServerStats struct
+//
+// Connection counters for monitoring
+//
+type ServerStats struct {
+	Active   int64  // connections handled right now
+	Queued   int64  // connections waiting for free slot
+	Accepted uint64 // connections accepted since start
+	Rejected uint64 // connections answered with 503
+}
+
+// Stats function
+//
+// Returns connection counters of the application
+//
+// Params:
+// - None
+//
+// Response:
+// - stats {ServerStats}
+//
+func (banjo Banjo) Stats() ServerStats {
+	if banjo.server == nil {
+		return ServerStats{}
+	}
+
+	return ServerStats{
+		Active:   atomic.LoadInt64(&banjo.server.active),
+		Queued:   atomic.LoadInt64(&banjo.server.queued),
+		Accepted: atomic.LoadUint64(&banjo.server.accepted),
+		Rejected: atomic.LoadUint64(&banjo.server.rejected),
+	}
+}
+
+// handleConn function
+//
+// Passes accepted connection to worker pool or new goroutine,
+// accepting is paused while connection waits for free slot,
+// connection is answered with 503 if slot isn't freed in Config.QueueTimeout
+//
+// Params:
+// - conn {net.Conn}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) handleConn(conn net.Conn) {
+	state := banjo.server
+	if state == nil {
+		go banjo.handleRequest(conn)
+		return
+	}
+
+	atomic.AddUint64(&state.accepted, 1)
+
+	if !state.acquire(banjo.config.QueueTimeout) {
+		atomic.AddUint64(&state.rejected, 1)
+		go banjo.rejectBusy(conn)
+		return
+	}
+
+	if state.work != nil {
+		state.workers.Do(func() {
+			for i := 0; i < banjo.config.Workers; i++ {
+				go banjo.worker()
+			}
+		})
+
+		select {
+		case state.work <- conn:
+		case <-state.ctx.Done():
+			state.release()
+			conn.Close()
+		}
+
+		return
+	}
+
+	go func() {
+		defer state.release()
+		banjo.handleRequest(conn)
+	}()
+}
+
+// worker function
+//
+// Handles connections from worker pool queue
+// until server is shut down
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (banjo Banjo) worker() {
+	state := banjo.server
+
+	for {
+		select {
+		case conn := <-state.work:
+			banjo.handleRequest(conn)
+			state.release()
+		case <-state.ctx.Done():
+			return
+		}
+	}
+}
+
+// rejectBusy function
+//
+// Answers connection with 503 & Retry-After header
+//
+// Params:
+// - conn {net.Conn}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) rejectBusy(conn net.Conn) {
+	retry := banjo.config.RetryAfter
+	if retry <= 0 {
+		retry = DefaultRetryAfter
+	}
+
+	response := Response{
+		Status:  503,
+		Body:    "Service Unavailable",
+		Headers: map[string]string{"Retry-After": strconv.FormatInt(ceilSeconds(retry), 10)},
+	}
+
+	addRequiredHeaders(&response)
+	response.Headers["Content-Length"] = strconv.Itoa(len(response.Body))
+
+	conn.SetWriteDeadline(time.Now().Add(rejectWriteTimeout))
+	conn.Write([]byte(banjo.parser.Response(response)))
+
+	// unread request data would reset connection
+	// before client receives the response
+	if closer, ok := conn.(interface{ CloseWrite() error }); ok {
+		closer.CloseWrite()
+		conn.SetReadDeadline(time.Now().Add(rejectDrainTimeout))
+		io.Copy(ioutil.Discard, conn)
+	}
+
+	conn.Close()
+}
+
+// acquire function
+//
+// Takes connection slot, waits up to timeout if all slots are busy
+//
+// Params:
+// - timeout {time.Duration}
+//
+// Response:
+// - ok {bool} false if slot wasn't freed in time
+//
+func (state *serverState) acquire(timeout time.Duration) bool {
+	if state.slots == nil {
+		atomic.AddInt64(&state.active, 1)
+		return true
+	}
+
+	select {
+	case state.slots <- struct{}{}:
+		atomic.AddInt64(&state.active, 1)
+		return true
+	default:
+	}
+
+	if timeout <= 0 {
+		return false
+	}
+
+	atomic.AddInt64(&state.queued, 1)
+	defer atomic.AddInt64(&state.queued, -1)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case state.slots <- struct{}{}:
+		atomic.AddInt64(&state.active, 1)
+		return true
+	case <-timer.C:
+		return false
+	case <-state.ctx.Done():
+		return false
+	}
+}
+
+// release function
+//
+// Frees connection slot taken by acquire
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (state *serverState) release() {
+	atomic.AddInt64(&state.active, -1)
+
+	if state.slots != nil {
+		<-state.slots
+	}
+}
@@ -0,0 +1,119 @@
+package banjo
+
+import (
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func servePool(t *testing.T, cnf Config, closure func(ctx *Context)) (Banjo, string) {
+	app := Create(cnf)
+	app.Get("/foo", closure)
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listener should be created")
+	}
+
+	go app.Serve(server)
+
+	return app, server.Addr().String()
+}
+
+func requestPool(t *testing.T, addr string) string {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Connection should be established")
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("GET /foo HTTP/1.1\r\n\r\n"))
+	data, _ := ioutil.ReadAll(conn)
+
+	return string(data)
+}
+
+func TestMaxConnectionsRejectsWith503(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.MaxConnections = 1
+	cnf.RetryAfter = 1500 * time.Millisecond
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	app, addr := servePool(t, cnf, func(ctx *Context) {
+		close(started)
+		<-release
+		ctx.HTML("ok")
+	})
+	defer app.Shutdown()
+
+	first := make(chan string, 1)
+	go func() {
+		first <- requestPool(t, addr)
+	}()
+	<-started
+
+	data := requestPool(t, addr)
+	if !strings.HasPrefix(data, "HTTP/1.1 503\r\n") || !strings.Contains(data, "Retry-After: 2\r\n") {
+		t.Errorf("Saturated server should answer 503 with Retry-After")
+	}
+
+	stats := app.Stats()
+	if stats.Active != 1 || stats.Accepted != 2 || stats.Rejected != 1 {
+		t.Errorf("Stats should count active & rejected connections")
+	}
+
+	close(release)
+
+	if !strings.HasSuffix(<-first, "ok") {
+		t.Errorf("Handled request should be answered")
+	}
+}
+
+func TestWorkerPoolQueuesConnections(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.Workers = 2
+	cnf.QueueTimeout = 5 * time.Second
+
+	var current, peak int64
+
+	app, addr := servePool(t, cnf, func(ctx *Context) {
+		n := atomic.AddInt64(&current, 1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt64(&current, -1)
+		ctx.HTML("ok")
+	})
+	defer app.Shutdown()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !strings.HasPrefix(requestPool(t, addr), "HTTP/1.1 200\r\n") {
+				t.Errorf("Queued request should be handled")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if atomic.LoadInt64(&peak) > 2 {
+		t.Errorf("Only 2 requests should be handled at the same time")
+	}
+
+	if app.Stats().Rejected != 0 {
+		t.Errorf("Queued requests should not be rejected")
+	}
+}
@@ -0,0 +1,284 @@
+package banjo
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// RemoteAddr function
+//
+// Returns network address of connected client or proxy
+//
+// Params:
+// - None
+//
+// Response:
+// - addr {string} "host:port", empty string if unknown
+//
+func (ctx *Context) RemoteAddr() string {
+	if ctx.remote != "" {
+		return ctx.remote
+	}
+
+	if ctx.httpRequest != nil {
+		return ctx.httpRequest.RemoteAddr
+	}
+
+	if ctx.conn != nil {
+		return ctx.conn.RemoteAddr().String()
+	}
+
+	return ""
+}
+
+// ClientIP function
+//
+// Returns client IP, Forwarded, X-Forwarded-For & X-Real-IP
+// headers are used only if request came from Config.TrustedProxies,
+// forwarded chain is read from the right skipping trusted proxies
+//
+// Params:
+// - None
+//
+// Response:
+// - ip {string}
+//
+func (ctx *Context) ClientIP() string {
+	remote := hostOnly(ctx.RemoteAddr())
+	if !ctx.trusted(remote) {
+		return remote
+	}
+
+	chain := forwardedValues(ctx.Request.Header("Forwarded"), "for")
+	if len(chain) == 0 {
+		chain = splitHeaderList(ctx.Request.Header("X-Forwarded-For"))
+	}
+
+	if len(chain) == 0 {
+		if ip := hostOnly(ctx.Request.Header("X-Real-IP")); ip != "" {
+			return ip
+		}
+
+		return remote
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := hostOnly(chain[i])
+		if !ctx.trusted(ip) {
+			return ip
+		}
+	}
+
+	return hostOnly(chain[0])
+}
+
+// Scheme function
+//
+// Returns request scheme, "https" for TLS connections,
+// Forwarded proto & X-Forwarded-Proto headers are used
+// only if request came from Config.TrustedProxies
+//
+// Params:
+// - None
+//
+// Response:
+// - scheme {string} "http" or "https"
+//
+func (ctx *Context) Scheme() string {
+	if ctx.trusted(hostOnly(ctx.RemoteAddr())) {
+		if proto := forwardedValues(ctx.Request.Header("Forwarded"), "proto"); len(proto) > 0 {
+			return strings.ToLower(proto[0])
+		}
+
+		if proto := splitHeaderList(ctx.Request.Header("X-Forwarded-Proto")); len(proto) > 0 {
+			return strings.ToLower(proto[0])
+		}
+	}
+
+	if ctx.tlsState != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// Host function
+//
+// Returns host requested by client, Forwarded host &
+// X-Forwarded-Host headers are used only if request
+// came from Config.TrustedProxies
+//
+// Params:
+// - None
+//
+// Response:
+// - host {string} host with optional port
+//
+func (ctx *Context) Host() string {
+	if ctx.trusted(hostOnly(ctx.RemoteAddr())) {
+		if host := forwardedValues(ctx.Request.Header("Forwarded"), "host"); len(host) > 0 {
+			return host[0]
+		}
+
+		if host := splitHeaderList(ctx.Request.Header("X-Forwarded-Host")); len(host) > 0 {
+			return host[0]
+		}
+	}
+
+	return ctx.Request.Header("Host")
+}
+
+// trusted function
+//
+// Checks if IP belongs to Config.TrustedProxies
+//
+// Params:
+// - ip {string}
+//
+// Response:
+// - ok {bool}
+//
+func (ctx *Context) trusted(ip string) bool {
+	return containsIP(ctx.proxies, net.ParseIP(ip))
+}
+
+// containsIP function
+//
+// Checks if IP belongs to one of networks
+//
+// Params:
+// - networks {[]*net.IPNet}
+// - ip       {net.IP}
+//
+// Response:
+// - ok {bool} false for nil IP
+//
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseNetworks function
+//
+// Parses list of IPs & CIDRs, invalid entries are skipped
+//
+// Params:
+// - list {[]string}
+//
+// Response:
+// - networks {[]*net.IPNet}
+// - err      {error} first invalid entry
+//
+func parseNetworks(list []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	var err error
+
+	for _, entry := range list {
+		entry = strings.TrimSpace(entry)
+
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 8*net.IPv4len
+				}
+
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+
+		_, network, e := net.ParseCIDR(entry)
+		if e != nil {
+			if err == nil {
+				err = fmt.Errorf("invalid IP or CIDR %q", entry)
+			}
+
+			continue
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, err
+}
+
+// forwardedValues function
+//
+// Returns values of parameter from RFC 7239 Forwarded header
+// in order of proxy chain
+//
+// Params:
+// - header {string} Forwarded header value
+// - name   {string} parameter name like "for" or "proto"
+//
+// Response:
+// - values {[]string}
+//
+func forwardedValues(header string, name string) []string {
+	var values []string
+
+	for _, element := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		pair := strings.SplitN(strings.TrimSpace(element), "=", 2)
+		if len(pair) != 2 || !strings.EqualFold(pair[0], name) {
+			continue
+		}
+
+		value := strings.Trim(strings.TrimSpace(pair[1]), "\"")
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// splitHeaderList function
+//
+// Splits comma separated header value
+//
+// Params:
+// - header {string}
+//
+// Response:
+// - values {[]string} non-empty trimmed values
+//
+func splitHeaderList(header string) []string {
+	var values []string
+
+	for _, value := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// hostOnly function
+//
+// Strips port & IPv6 brackets from address
+//
+// Params:
+// - addr {string} "host:port", "[ipv6]:port" or "host"
+//
+// Response:
+// - host {string}
+//
+func hostOnly(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+}
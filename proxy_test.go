@@ -0,0 +1,76 @@
+package banjo
+
+import "testing"
+
+func proxyContext(remote string, headers map[string]string) *Context {
+	proxies, _ := parseNetworks([]string{"10.0.0.0/8", "192.168.1.1"})
+
+	return &Context{
+		Request: Request{Headers: headers},
+		remote:  remote,
+		proxies: proxies,
+	}
+}
+
+func TestClientIPIgnoresUntrustedHeaders(t *testing.T) {
+	ctx := proxyContext("203.0.113.5:1234", map[string]string{"X-Forwarded-For": "1.2.3.4"})
+
+	if ctx.ClientIP() != "203.0.113.5" {
+		t.Errorf("Headers from untrusted peer should be ignored")
+	}
+
+	if ctx.RemoteAddr() != "203.0.113.5:1234" {
+		t.Errorf("RemoteAddr should be connection address")
+	}
+}
+
+func TestClientIPSkipsTrustedProxies(t *testing.T) {
+	ctx := proxyContext("10.0.0.2:1234", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.2.3.4, 192.168.1.1"})
+
+	if ctx.ClientIP() != "1.2.3.4" {
+		t.Errorf("First untrusted address from the right should be client IP")
+	}
+}
+
+func TestClientIPFromForwardedHeader(t *testing.T) {
+	ctx := proxyContext("10.0.0.2:1234", map[string]string{
+		"Forwarded":       `for="[2001:db8:cafe::17]:4711";proto=https;host=example.com, for=10.1.1.1`,
+		"X-Forwarded-For": "1.2.3.4",
+	})
+
+	if ctx.ClientIP() != "2001:db8:cafe::17" {
+		t.Errorf("Forwarded header should be preferred")
+	}
+
+	if ctx.Scheme() != "https" || ctx.Host() != "example.com" {
+		t.Errorf("Scheme & host should be taken from Forwarded header")
+	}
+}
+
+func TestClientIPFromRealIP(t *testing.T) {
+	ctx := proxyContext("192.168.1.1:1234", map[string]string{"X-Real-IP": "1.2.3.4"})
+
+	if ctx.ClientIP() != "1.2.3.4" {
+		t.Errorf("X-Real-IP should be used")
+	}
+}
+
+func TestSchemeAndHostFromUntrustedPeer(t *testing.T) {
+	ctx := proxyContext("203.0.113.5:1234", map[string]string{
+		"Host":              "example.com",
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "evil.com",
+	})
+
+	if ctx.Scheme() != "http" || ctx.Host() != "example.com" {
+		t.Errorf("Forwarded scheme & host from untrusted peer should be ignored")
+	}
+}
+
+func TestParseTrustedProxiesReportsInvalidEntry(t *testing.T) {
+	networks, err := parseNetworks([]string{"10.0.0.0/8", "foo", "::1"})
+
+	if err == nil || len(networks) != 2 {
+		t.Errorf("Invalid entry should be reported & skipped")
+	}
+}
@@ -0,0 +1,343 @@
+package banjo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyHeaderTimeout is time allowed to receive PROXY protocol
+// header when Config.ReadHeaderTimeout isn't set
+const proxyHeaderTimeout = 10 * time.Second
+
+// proxySignature is PROXY protocol v2 header signature
+var proxySignature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// errProxyHeader is returned for missing or malformed PROXY protocol header
+var errProxyHeader = errors.New("banjo: invalid PROXY protocol header")
+
+// errProxyUntrusted is returned by Run & RunTLS when
+// ProxyProtocol is enabled without TrustedProxies
+var errProxyUntrusted = errors.New("banjo: ProxyProtocol requires TrustedProxies")
+
+// proxyListener struct
+//
+// Listener which reads PROXY protocol header
+// from connections of trusted load balancers
+//
+type proxyListener struct {
+	net.Listener
+	proxies []*net.IPNet
+	timeout time.Duration
+}
+
+// proxyConn struct
+//
+// Connection with client address taken from PROXY protocol header,
+// header is read on first Read or RemoteAddr call
+//
+type proxyConn struct {
+	net.Conn
+	reader   *bufio.Reader
+	timeout  time.Duration
+	once     sync.Once
+	err      error
+	remote   net.Addr
+	mutex    sync.Mutex
+	deadline time.Time
+}
+
+// ProxyListener function
+//
+// Wraps listener to accept PROXY protocol v1 & v2 headers,
+// header is required from Config.TrustedProxies, connections
+// from other peers are used as is, so nothing is wrapped
+// if list is empty, Run & RunTLS wrap listener when
+// Config.ProxyProtocol is enabled & refuse to start without
+// TrustedProxies
+//
+// Params:
+// - listener {net.Listener}
+//
+// Response:
+// - listener {net.Listener}
+//
+func (banjo Banjo) ProxyListener(listener net.Listener) net.Listener {
+	timeout := banjo.config.ReadHeaderTimeout
+	if timeout <= 0 {
+		timeout = proxyHeaderTimeout
+	}
+
+	return &proxyListener{Listener: listener, proxies: banjo.proxies, timeout: timeout}
+}
+
+// Accept function
+//
+// Waits for next connection, wraps connections of trusted peers
+//
+// Params:
+// - None
+//
+// Response:
+// - conn {net.Conn}
+// - err  {error}
+//
+func (listener *proxyListener) Accept() (net.Conn, error) {
+	conn, err := listener.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsIP(listener.proxies, net.ParseIP(hostOnly(conn.RemoteAddr().String()))) {
+		return conn, nil
+	}
+
+	return &proxyConn{Conn: conn, reader: bufio.NewReader(conn), timeout: listener.timeout}, nil
+}
+
+// Read function
+//
+// Reads connection data following PROXY protocol header
+//
+// Params:
+// - data {[]byte}
+//
+// Response:
+// - n   {int}
+// - err {error}
+//
+func (conn *proxyConn) Read(data []byte) (int, error) {
+	if err := conn.init(); err != nil {
+		return 0, err
+	}
+
+	return conn.reader.Read(data)
+}
+
+// RemoteAddr function
+//
+// Returns client address from PROXY protocol header
+//
+// Params:
+// - None
+//
+// Response:
+// - addr {net.Addr}
+//
+func (conn *proxyConn) RemoteAddr() net.Addr {
+	conn.init()
+
+	if conn.remote != nil {
+		return conn.remote
+	}
+
+	return conn.Conn.RemoteAddr()
+}
+
+// SetDeadline function
+//
+// Sets read & write deadlines, read deadline
+// is restored after PROXY protocol header is read
+//
+// Params:
+// - t {time.Time}
+//
+// Response:
+// - err {error}
+//
+func (conn *proxyConn) SetDeadline(t time.Time) error {
+	conn.mutex.Lock()
+	conn.deadline = t
+	conn.mutex.Unlock()
+
+	return conn.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline function
+//
+// Sets read deadline, it's restored after
+// PROXY protocol header is read
+//
+// Params:
+// - t {time.Time}
+//
+// Response:
+// - err {error}
+//
+func (conn *proxyConn) SetReadDeadline(t time.Time) error {
+	conn.mutex.Lock()
+	conn.deadline = t
+	conn.mutex.Unlock()
+
+	return conn.Conn.SetReadDeadline(t)
+}
+
+// init function
+//
+// Reads PROXY protocol header once, connection is closed
+// if header is missing or malformed
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error}
+//
+func (conn *proxyConn) init() error {
+	conn.once.Do(func() {
+		conn.mutex.Lock()
+		deadline := conn.deadline
+		conn.mutex.Unlock()
+
+		limit := time.Now().Add(conn.timeout)
+		if deadline.IsZero() || limit.Before(deadline) {
+			conn.Conn.SetReadDeadline(limit)
+		}
+
+		conn.remote, conn.err = readProxyHeader(conn.reader)
+
+		conn.mutex.Lock()
+		conn.Conn.SetReadDeadline(conn.deadline)
+		conn.mutex.Unlock()
+
+		if conn.err != nil {
+			conn.Conn.Close()
+		}
+	})
+
+	return conn.err
+}
+
+// readProxyHeader function
+//
+// Reads PROXY protocol v1 or v2 header
+//
+// Params:
+// - reader {*bufio.Reader}
+//
+// Response:
+// - addr {net.Addr} client address, nil for LOCAL & UNKNOWN headers
+// - err  {error}
+//
+func readProxyHeader(reader *bufio.Reader) (net.Addr, error) {
+	start, err := reader.Peek(len(proxySignature))
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(start, proxySignature) {
+		return readProxyV2(reader)
+	}
+
+	if bytes.HasPrefix(start, []byte("PROXY ")) {
+		return readProxyV1(reader)
+	}
+
+	return nil, errProxyHeader
+}
+
+// readProxyV1 function
+//
+// Reads text header like "PROXY TCP4 1.2.3.4 10.0.0.1 5678 80\r\n"
+//
+// Params:
+// - reader {*bufio.Reader}
+//
+// Response:
+// - addr {net.Addr}
+// - err  {error}
+//
+func readProxyV1(reader *bufio.Reader) (net.Addr, error) {
+	var line []byte
+
+	for len(line) < 107 {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errProxyHeader
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, errProxyHeader
+	}
+
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if ip == nil || err != nil || (fields[1] == "TCP4") != (ip.To4() != nil) {
+		return nil, errProxyHeader
+	}
+
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyV2 function
+//
+// Reads binary header, TLV extensions are skipped
+//
+// Params:
+// - reader {*bufio.Reader}
+//
+// Response:
+// - addr {net.Addr}
+// - err  {error}
+//
+func readProxyV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	version, command, family := header[12]>>4, header[12]&0x0f, header[13]
+	length := int(binary.BigEndian.Uint16(header[14:16]))
+
+	if version != 2 || command > 1 {
+		return nil, errProxyHeader
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+
+	if command == 0 {
+		return nil, nil
+	}
+
+	switch family {
+	case 0x11, 0x12:
+		if length < 12 {
+			return nil, errProxyHeader
+		}
+
+		return &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}, nil
+	case 0x21, 0x22:
+		if length < 36 {
+			return nil, errProxyHeader
+		}
+
+		return &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,124 @@
+package banjo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+)
+
+func serveProxy(t *testing.T, trusted []string) (Banjo, string) {
+	cnf := DefaultConfig()
+	cnf.TrustedProxies = trusted
+	app := Create(cnf)
+
+	app.Get("/ip", func(ctx *Context) {
+		ctx.HTML(ctx.ClientIP())
+	})
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listener should be created")
+	}
+
+	go app.Serve(app.ProxyListener(server))
+
+	return app, server.Addr().String()
+}
+
+func requestProxy(t *testing.T, addr string, data string) string {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Connection should be established")
+	}
+	defer conn.Close()
+
+	conn.Write([]byte(data))
+	response, _ := ioutil.ReadAll(conn)
+
+	return string(response)
+}
+
+func TestProxyProtocolV1(t *testing.T) {
+	app, addr := serveProxy(t, []string{"127.0.0.1"})
+	defer app.Shutdown()
+
+	response := requestProxy(t, addr, "PROXY TCP4 203.0.113.7 10.0.0.1 5678 80\r\nGET /ip HTTP/1.1\r\n\r\n")
+	if !strings.HasSuffix(response, "203.0.113.7") {
+		t.Errorf("Client address should be taken from PROXY header")
+	}
+
+	response = requestProxy(t, addr, "GET /ip HTTP/1.1\r\n\r\n")
+	if response != "" {
+		t.Errorf("Connection without PROXY header from trusted peer should be closed")
+	}
+}
+
+func TestProxyProtocolUntrustedPeer(t *testing.T) {
+	app, addr := serveProxy(t, []string{"10.0.0.0/8"})
+	defer app.Shutdown()
+
+	response := requestProxy(t, addr, "GET /ip HTTP/1.1\r\n\r\n")
+	if !strings.HasSuffix(response, "127.0.0.1") {
+		t.Errorf("Connection from untrusted peer should be used as is")
+	}
+}
+
+func TestProxyProtocolWithoutTrustedProxies(t *testing.T) {
+	app, addr := serveProxy(t, nil)
+	defer app.Shutdown()
+
+	response := requestProxy(t, addr, "PROXY TCP4 203.0.113.7 10.0.0.1 5678 80\r\nGET /ip HTTP/1.1\r\n\r\n")
+	if strings.Contains(response, "203.0.113.7") {
+		t.Errorf("PROXY header shouldn't be accepted without trusted proxies")
+	}
+
+	cnf := DefaultConfig()
+	cnf.Address = "127.0.0.1:0"
+	cnf.ProxyProtocol = true
+
+	if err := Create(cnf).Run(); err != errProxyUntrusted {
+		t.Errorf("Run should refuse to start without trusted proxies, got %v", err)
+	}
+}
+
+func TestProxyProtocolV2(t *testing.T) {
+	var header bytes.Buffer
+	header.Write(proxySignature)
+	header.Write([]byte{0x21, 0x11, 0, 12})
+	header.Write(net.ParseIP("198.51.100.2").To4())
+	header.Write(net.ParseIP("10.0.0.1").To4())
+	binary.Write(&header, binary.BigEndian, uint16(4242))
+	binary.Write(&header, binary.BigEndian, uint16(443))
+	header.WriteString("GET / HTTP/1.1\r\n")
+
+	reader := bufio.NewReader(&header)
+	addr, err := readProxyHeader(reader)
+
+	if err != nil || addr.String() != "198.51.100.2:4242" {
+		t.Errorf("Client address should be parsed from v2 header")
+	}
+
+	if line, _ := reader.ReadString('\n'); line != "GET / HTTP/1.1\r\n" {
+		t.Errorf("Data after header should be kept")
+	}
+}
+
+func TestProxyProtocolMalformedHeader(t *testing.T) {
+	for _, data := range []string{
+		"PROXY TCP4 foo 10.0.0.1 5678 80\r\n",
+		"PROXY TCP6 1.2.3.4 10.0.0.1 5678 80\r\n",
+		"GET / HTTP/1.1\r\n\r\n",
+	} {
+		if _, err := readProxyHeader(bufio.NewReader(strings.NewReader(data))); err == nil {
+			t.Errorf("Malformed header should be rejected: %q", data)
+		}
+	}
+
+	if addr, err := readProxyHeader(bufio.NewReader(strings.NewReader("PROXY UNKNOWN\r\n"))); err != nil || addr != nil {
+		t.Errorf("UNKNOWN header should keep connection address")
+	}
+}
@@ -0,0 +1,432 @@
+package banjo
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter interface
+//
+// Keeps rate limit state, in-memory implementations are
+// returned by NewTokenBucket & NewSlidingWindow,
+// shared stores can implement the same interface
+//
+type RateLimiter interface {
+	// Allow registers request for key
+	Allow(key string) (RateLimitResult, error)
+}
+
+// RateLimitResult struct
+//
+// Limiter decision for single request
+//
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // time until limit is fully restored
+	RetryAfter time.Duration // time until next request is allowed
+}
+
+// RateLimitKey type is func(ctx *Context) string alias
+//
+// Returns key requests are counted by
+//
+type RateLimitKey func(ctx *Context) string
+
+// tokenBucket struct
+//
+// In-memory token bucket limiter
+//
+type tokenBucket struct {
+	mutex   sync.Mutex
+	rate    float64
+	burst   int
+	buckets map[string]*bucketState
+	swept   time.Time
+	now     func() time.Time
+}
+
+// bucketState struct
+//
+// Tokens left for single key
+//
+type bucketState struct {
+	tokens float64
+	last   time.Time
+}
+
+// slidingWindow struct
+//
+// In-memory sliding window counter limiter
+//
+type slidingWindow struct {
+	mutex   sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*windowState
+	swept   time.Time
+	now     func() time.Time
+}
+
+// windowState struct
+//
+// Request counters of current & previous windows for single key
+//
+type windowState struct {
+	start    time.Time
+	current  int
+	previous int
+}
+
+// RateLimit function
+//
+// Returns middleware which limits requests by key,
+// X-RateLimit-* headers are added to every response,
+// 429 with Retry-After is sent when limit is exceeded,
+// requests are allowed if limiter returns error
+// Example usage:
+// limiter := banjo.NewTokenBucket(10, 20)
+// app.Get("/api", banjo.Chain(api, banjo.RateLimit(limiter, banjo.KeyByHeader("X-Api-Key"))))
+//
+// Params:
+// - limiter {RateLimiter}
+// - key     {RateLimitKey} KeyByIP if nil
+//
+// Response:
+// - middleware {Middleware}
+//
+func RateLimit(limiter RateLimiter, key RateLimitKey) Middleware {
+	if key == nil {
+		key = KeyByIP()
+	}
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			result, err := limiter.Allow(key(ctx))
+			if err != nil {
+				next(ctx)
+				return
+			}
+
+			if ctx.Response.Headers == nil {
+				ctx.Response.Headers = make(map[string]string)
+			}
+
+			ctx.Response.Headers["X-RateLimit-Limit"] = strconv.Itoa(result.Limit)
+			ctx.Response.Headers["X-RateLimit-Remaining"] = strconv.Itoa(result.Remaining)
+			ctx.Response.Headers["X-RateLimit-Reset"] = strconv.FormatInt(ceilSeconds(result.Reset), 10)
+
+			if !result.Allowed {
+				ctx.Response.Headers["Retry-After"] = strconv.FormatInt(ceilSeconds(result.RetryAfter), 10)
+				ctx.Response.Headers["Content-Type"] = "text/plain"
+				ctx.Response.Status = http.StatusTooManyRequests
+				ctx.Response.Body = http.StatusText(http.StatusTooManyRequests)
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// KeyByIP function
+//
+// Returns key function counting requests by client IP,
+// proxy headers are honored for Config.TrustedProxies
+//
+// Params:
+// - None
+//
+// Response:
+// - key {RateLimitKey}
+//
+func KeyByIP() RateLimitKey {
+	return func(ctx *Context) string {
+		return ctx.ClientIP()
+	}
+}
+
+// KeyByHeader function
+//
+// Returns key function counting requests by header value
+// like API key, requests without header share one limit
+//
+// Params:
+// - name {string} header name
+//
+// Response:
+// - key {RateLimitKey}
+//
+func KeyByHeader(name string) RateLimitKey {
+	return func(ctx *Context) string {
+		return ctx.Request.Header(name)
+	}
+}
+
+// NewTokenBucket function
+//
+// Returns in-memory token bucket limiter,
+// bucket is refilled with rate tokens per second
+// and keeps up to burst tokens, zero rate never refills
+//
+// Params:
+// - rate  {float64} tokens per second
+// - burst {int} bucket size
+//
+// Response:
+// - limiter {RateLimiter}
+//
+func NewTokenBucket(rate float64, burst int) RateLimiter {
+	return &tokenBucket{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*bucketState),
+		now:     time.Now,
+	}
+}
+
+// Allow function
+//
+// Takes token from key bucket
+//
+// Params:
+// - key {string}
+//
+// Response:
+// - result {RateLimitResult}
+// - err    {error} always nil
+//
+func (limiter *tokenBucket) Allow(key string) (RateLimitResult, error) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := limiter.now()
+	limiter.sweep(now)
+
+	state, ok := limiter.buckets[key]
+	if !ok {
+		state = &bucketState{tokens: float64(limiter.burst), last: now}
+		limiter.buckets[key] = state
+	}
+
+	state.tokens = math.Min(float64(limiter.burst), state.tokens+now.Sub(state.last).Seconds()*limiter.rate)
+	state.last = now
+
+	result := RateLimitResult{Limit: limiter.burst}
+
+	if state.tokens >= 1 {
+		state.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = limiter.duration(1 - state.tokens)
+	}
+
+	result.Remaining = int(state.tokens)
+	result.Reset = limiter.duration(float64(limiter.burst) - state.tokens)
+
+	return result, nil
+}
+
+// duration function
+//
+// Returns time needed to refill given number of tokens
+//
+// Params:
+// - tokens {float64}
+//
+// Response:
+// - duration {time.Duration}
+//
+func (limiter *tokenBucket) duration(tokens float64) time.Duration {
+	if limiter.rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / limiter.rate * float64(time.Second))
+}
+
+// sweep function
+//
+// Removes full buckets, runs once per refill period,
+// buckets which are never refilled are kept
+//
+// Params:
+// - now {time.Time}
+//
+// Response:
+// - None
+//
+func (limiter *tokenBucket) sweep(now time.Time) {
+	period := limiter.duration(float64(limiter.burst))
+	if period <= 0 || now.Sub(limiter.swept) < period {
+		return
+	}
+
+	limiter.swept = now
+
+	for key, state := range limiter.buckets {
+		if now.Sub(state.last) >= period {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+// NewSlidingWindow function
+//
+// Returns in-memory sliding window limiter,
+// which allows up to limit requests per window
+//
+// Params:
+// - limit  {int} requests per window
+// - window {time.Duration}
+//
+// Response:
+// - limiter {RateLimiter}
+//
+func NewSlidingWindow(limit int, window time.Duration) RateLimiter {
+	return &slidingWindow{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*windowState),
+		now:     time.Now,
+	}
+}
+
+// Allow function
+//
+// Counts request in key window, previous window is weighted
+// by its part overlapping with sliding window
+//
+// Params:
+// - key {string}
+//
+// Response:
+// - result {RateLimitResult}
+// - err    {error} always nil
+//
+func (limiter *slidingWindow) Allow(key string) (RateLimitResult, error) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := limiter.now()
+	limiter.sweep(now)
+
+	start := now.Truncate(limiter.window)
+
+	state, ok := limiter.windows[key]
+	if !ok {
+		state = &windowState{start: start}
+		limiter.windows[key] = state
+	}
+
+	if !state.start.Equal(start) {
+		if start.Sub(state.start) == limiter.window {
+			state.previous = state.current
+		} else {
+			state.previous = 0
+		}
+
+		state.start = start
+		state.current = 0
+	}
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(limiter.window)
+	count := float64(state.previous)*weight + float64(state.current)
+
+	result := RateLimitResult{
+		Limit: limiter.limit,
+		Reset: limiter.window - elapsed,
+	}
+
+	if count+1 <= float64(limiter.limit) {
+		state.current++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = limiter.retryAfter(state, elapsed)
+	}
+
+	if state.current > 0 {
+		result.Reset += limiter.window
+	}
+
+	result.Remaining = limiter.limit - int(math.Ceil(count))
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+
+	return result, nil
+}
+
+// retryAfter function
+//
+// Returns time until weighted counter drops below limit
+//
+// Params:
+// - state   {*windowState}
+// - elapsed {time.Duration} time since current window start
+//
+// Response:
+// - duration {time.Duration}
+//
+func (limiter *slidingWindow) retryAfter(state *windowState, elapsed time.Duration) time.Duration {
+	free := float64(limiter.limit - 1 - state.current)
+	if state.previous == 0 || free < 0 {
+		return limiter.window - elapsed
+	}
+
+	at := time.Duration((1 - free/float64(state.previous)) * float64(limiter.window))
+	if at <= elapsed {
+		return 0
+	}
+
+	return at - elapsed
+}
+
+// sweep function
+//
+// Removes keys without requests in last two windows,
+// runs once per window
+//
+// Params:
+// - now {time.Time}
+//
+// Response:
+// - None
+//
+func (limiter *slidingWindow) sweep(now time.Time) {
+	if now.Sub(limiter.swept) < limiter.window {
+		return
+	}
+
+	limiter.swept = now
+
+	for key, state := range limiter.windows {
+		if now.Sub(state.start) >= 2*limiter.window {
+			delete(limiter.windows, key)
+		}
+	}
+}
+
+// ceilSeconds function
+//
+// Rounds duration up to whole seconds for HTTP headers
+//
+// Params:
+// - duration {time.Duration}
+//
+// Response:
+// - seconds {int64}
+//
+func ceilSeconds(duration time.Duration) int64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return int64((duration + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,103 @@
+package banjo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketZeroRate(t *testing.T) {
+	limiter := NewTokenBucket(0, 1)
+
+	if result, _ := limiter.Allow("foo"); !result.Allowed {
+		t.Errorf("First request should be allowed")
+	}
+
+	if result, _ := limiter.Allow("foo"); result.Allowed {
+		t.Errorf("Second request should be denied when bucket isn't refilled")
+	}
+}
+
+func TestTokenBucketRefills(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewTokenBucket(1, 2).(*tokenBucket)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if result, _ := limiter.Allow("foo"); !result.Allowed {
+			t.Errorf("Requests within burst should be allowed")
+		}
+	}
+
+	result, _ := limiter.Allow("foo")
+	if result.Allowed || result.RetryAfter != time.Second {
+		t.Errorf("Request over burst should be rejected")
+	}
+
+	if result, _ := limiter.Allow("bar"); !result.Allowed {
+		t.Errorf("Keys should have separate buckets")
+	}
+
+	now = now.Add(time.Second)
+	if result, _ := limiter.Allow("foo"); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Bucket should be refilled")
+	}
+}
+
+func TestSlidingWindowWeightsPreviousWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewSlidingWindow(4, time.Minute).(*slidingWindow)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		if result, _ := limiter.Allow("foo"); !result.Allowed {
+			t.Errorf("Requests within limit should be allowed")
+		}
+	}
+
+	if result, _ := limiter.Allow("foo"); result.Allowed {
+		t.Errorf("Request over limit should be rejected")
+	}
+
+	now = now.Truncate(time.Minute).Add(90 * time.Second)
+
+	if result, _ := limiter.Allow("foo"); !result.Allowed || result.Remaining != 1 {
+		t.Errorf("Half of previous window should be counted")
+	}
+
+	if result, _ := limiter.Allow("foo"); !result.Allowed {
+		t.Errorf("Request within limit should be allowed")
+	}
+
+	result, _ := limiter.Allow("foo")
+	if result.Allowed || result.RetryAfter != 15*time.Second {
+		t.Errorf("Request over limit should be rejected until previous window fades")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := NewSlidingWindow(1, time.Minute)
+	action := Chain(func(ctx *Context) {
+		ctx.HTML("ok")
+	}, RateLimit(limiter, KeyByHeader("X-Api-Key")))
+
+	ctx := testContext(Request{Headers: map[string]string{"X-Api-Key": "foo"}})
+	action(ctx)
+
+	if ctx.Response.Status != 200 || ctx.Response.Headers["X-RateLimit-Remaining"] != "0" {
+		t.Errorf("First request should be allowed")
+	}
+
+	ctx = testContext(Request{Headers: map[string]string{"X-Api-Key": "foo"}})
+	action(ctx)
+
+	if ctx.Response.Status != 429 || ctx.Response.Headers["Retry-After"] == "" {
+		t.Errorf("Second request should be rejected with 429")
+	}
+
+	ctx = testContext(Request{Headers: map[string]string{"X-Api-Key": "bar"}})
+	action(ctx)
+
+	if ctx.Response.Status != 200 || ctx.Response.Headers["X-RateLimit-Limit"] != "1" {
+		t.Errorf("Other key should be allowed")
+	}
+}
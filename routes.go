@@ -2,6 +2,7 @@ package banjo
 
 import (
 	"reflect"
+	"strings"
 )
 
 // Routes struct
@@ -18,8 +19,13 @@ type Routes struct {
 	OPTIONS map[string]func(ctx *Context)
 	HEAD    map[string]func(ctx *Context)
 	DELETE  map[string]func(ctx *Context)
+
+	mounts map[string]func(ctx *Context)
 }
 
+// routeMethods are names of Routes method tables
+var routeMethods = []string{"GET", "POST", "PUT", "PATCH", "OPTIONS", "HEAD", "DELETE"}
+
 // CreateRoutes function
 //
 // Create Routes with empty method tables
@@ -39,6 +45,7 @@ func CreateRoutes() Routes {
 		OPTIONS: make(map[string]func(ctx *Context)),
 		HEAD:    make(map[string]func(ctx *Context)),
 		DELETE:  make(map[string]func(ctx *Context)),
+		mounts:  make(map[string]func(ctx *Context)),
 	}
 }
 
@@ -56,17 +63,93 @@ func CreateRoutes() Routes {
 func (routes Routes) Block(method string, url string) func(ctx *Context) {
 	object := reflect.ValueOf(routes)
 	value := reflect.Indirect(object).FieldByName(method)
-	intf := value.Interface()
-	table := intf.(map[string]func(ctx *Context))
 
-	block, ok := table[url]
-	if ok {
+	if value.IsValid() && value.CanInterface() {
+		table := value.Interface().(map[string]func(ctx *Context))
+
+		block, ok := table[url]
+		if ok {
+			return block
+		}
+	}
+
+	if block := routes.mounted(url); block != nil {
 		return block
 	}
 
 	return notFound()
 }
 
+// Methods function
+//
+// Returns methods registered for url,
+// mounted closures are not included
+//
+// Params:
+// - url {string} HTTP Request URL
+//
+// Response:
+// - methods {[]string}
+//
+func (routes Routes) Methods(url string) []string {
+	var methods []string
+
+	for _, method := range routeMethods {
+		if _, ok := routes.table(method)[url]; ok {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+// Mount function
+//
+// Adding closure which handles all methods
+// for url equal to prefix or starting with prefix + "/"
+//
+// Params:
+// - prefix  {string} URL prefix
+// - closure {func(ctx *Context)}
+//
+// Response:
+// - None
+//
+func (routes Routes) Mount(prefix string, closure func(ctx *Context)) {
+	routes.mounts[strings.TrimSuffix(prefix, "/")] = closure
+}
+
+// mounted function
+//
+// Returns closure mounted with the longest prefix matching url
+//
+// Params:
+// - url {string} HTTP Request URL
+//
+// Response:
+// - closure {func(ctx *Context)} nil if nothing mounted
+//
+func (routes Routes) mounted(url string) func(ctx *Context) {
+	var block func(ctx *Context)
+	longest := -1
+
+	if index := strings.IndexAny(url, "?#"); index >= 0 {
+		url = url[:index]
+	}
+
+	for prefix, closure := range routes.mounts {
+		if len(prefix) <= longest {
+			continue
+		}
+
+		if url == prefix || strings.HasPrefix(url, prefix+"/") {
+			block, longest = closure, len(prefix)
+		}
+	}
+
+	return block
+}
+
 // Push function
 //
 // Adding new element to one of fields in Routes struct
@@ -88,6 +171,48 @@ func (routes Routes) Push(method string, url string, closure func(ctx *Context))
 	value.SetMapIndex(reflect.ValueOf(url), reflect.ValueOf(closure))
 }
 
+// table function
+//
+// Returns closures table for method
+//
+// Params:
+// - method {string} HTTP Request Method
+//
+// Response:
+// - table {map[string]func(ctx *Context)} nil for unknown method
+//
+func (routes Routes) table(method string) map[string]func(ctx *Context) {
+	value := reflect.ValueOf(routes).FieldByName(method)
+
+	if value.IsValid() && value.CanInterface() {
+		return value.Interface().(map[string]func(ctx *Context))
+	}
+
+	return nil
+}
+
+// allowMethods function
+//
+// Returns closure answering OPTIONS request
+// for url registered with other methods
+//
+// Params:
+// - methods {[]string} registered methods
+//
+// Response:
+// - closure {func(ctx *Context)}
+//
+func allowMethods(methods []string) func(ctx *Context) {
+	return func(ctx *Context) {
+		if ctx.Response.Headers == nil {
+			ctx.Response.Headers = make(map[string]string)
+		}
+
+		ctx.Response.Headers["Allow"] = strings.Join(append(methods, "OPTIONS"), ", ")
+		ctx.Response.Status = 204
+	}
+}
+
 // notFound function
 //
 // Returns default error page Response if
@@ -38,3 +38,28 @@ func TestFullRoutesUsageStruct(t *testing.T) {
 		t.Errorf("Response Status should be 200")
 	}
 }
+
+func TestUnknownMethodIsNotFound(t *testing.T) {
+	routes := CreateRoutes()
+	ctx := &Context{}
+	routes.Block("mounts", "/foo")(ctx)
+
+	if ctx.Response.Status != 404 {
+		t.Errorf("Response Status should be 404")
+	}
+}
+
+func TestRoutesMethods(t *testing.T) {
+	routes := CreateRoutes()
+	routes.Push("GET", "/foo", func(ctx *Context) {})
+	routes.Push("POST", "/foo", func(ctx *Context) {})
+
+	methods := routes.Methods("/foo")
+	if len(methods) != 2 || methods[0] != "GET" || methods[1] != "POST" {
+		t.Errorf("Registered methods should be returned")
+	}
+
+	if len(routes.Methods("/bar")) != 0 {
+		t.Errorf("Unknown url should have no methods")
+	}
+}
@@ -0,0 +1,151 @@
+package banjo
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NoncePlaceholder is replaced with per-request
+// nonce in SecurityOptions.ContentSecurityPolicy
+const NoncePlaceholder = "{nonce}"
+
+// cspNonceKey is Context store key of CSP nonce
+const cspNonceKey = "banjo.csp_nonce"
+
+// SecurityOptions struct
+//
+// Values of security headers, empty value disables header
+//
+type SecurityOptions struct {
+	// StrictTransportSecurity is sent only for HTTPS requests
+	StrictTransportSecurity string
+
+	// ContentSecurityPolicy can contain NoncePlaceholder,
+	// nonce is available in closure by ctx.CSPNonce()
+	ContentSecurityPolicy string
+
+	ContentTypeOptions string
+	FrameOptions       string
+	ReferrerPolicy     string
+	PermissionsPolicy  string
+}
+
+// DefaultSecurityOptions function
+//
+// Returns strict security headers values
+//
+// Params:
+// - None
+//
+// Response:
+// - options {SecurityOptions}
+//
+func DefaultSecurityOptions() SecurityOptions {
+	return SecurityOptions{
+		StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+		ContentSecurityPolicy:   "default-src 'self'; script-src 'self' 'nonce-" + NoncePlaceholder + "'; object-src 'none'; frame-ancestors 'none'",
+		ContentTypeOptions:      "nosniff",
+		FrameOptions:            "DENY",
+		ReferrerPolicy:          "strict-origin-when-cross-origin",
+		PermissionsPolicy:       "camera=(), microphone=(), geolocation=()",
+	}
+}
+
+// SecureHeaders function
+//
+// Returns middleware which adds security headers right before
+// response is written, so they are sent with every response,
+// headers set by closure are kept
+// Example usage:
+// app.Use(banjo.SecureHeaders(banjo.DefaultSecurityOptions()))
+//
+// Params:
+// - options {SecurityOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func SecureHeaders(options SecurityOptions) Middleware {
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			policy := options.ContentSecurityPolicy
+
+			if strings.Contains(policy, NoncePlaceholder) {
+				nonce, err := cspNonce()
+				if err != nil {
+					CreateLogger().Error(fmt.Sprintf("Error while generating CSP nonce:\nError: %v", err))
+					ctx.InternalServerError()
+					return
+				}
+
+				ctx.Set(cspNonceKey, nonce)
+				policy = strings.Replace(policy, NoncePlaceholder, nonce, -1)
+			}
+
+			ctx.onHeaders(func(ctx *Context) {
+				headers := map[string]string{
+					"Content-Security-Policy": policy,
+					"X-Content-Type-Options":  options.ContentTypeOptions,
+					"X-Frame-Options":         options.FrameOptions,
+					"Referrer-Policy":         options.ReferrerPolicy,
+					"Permissions-Policy":      options.PermissionsPolicy,
+				}
+
+				if ctx.Scheme() == "https" {
+					headers["Strict-Transport-Security"] = options.StrictTransportSecurity
+				}
+
+				for k, v := range headers {
+					if _, ok := ctx.Response.Headers[k]; !ok && v != "" {
+						ctx.Response.Headers[k] = v
+					}
+				}
+			})
+
+			next(ctx)
+		}
+	}
+}
+
+// CSPNonce function
+//
+// Returns Content-Security-Policy nonce of the current request
+// for inline scripts & styles in templates
+// Example usage:
+// ctx.HTML(`<script nonce="` + ctx.CSPNonce() + `">...</script>`)
+//
+// Params:
+// - None
+//
+// Response:
+// - nonce {string} empty if SecureHeaders policy has no nonce
+//
+func (ctx *Context) CSPNonce() string {
+	nonce, _ := GetAs[string](ctx, cspNonceKey)
+
+	return nonce
+}
+
+// cspNonce function
+//
+// Generates random base64 nonce
+//
+// Params:
+// - None
+//
+// Response:
+// - nonce {string}
+// - err   {error} random source error
+//
+func cspNonce() (string, error) {
+	data := make([]byte, 16)
+
+	if _, err := io.ReadFull(rand.Reader, data); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
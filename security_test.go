@@ -0,0 +1,92 @@
+package banjo
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestSecureHeadersAddedBeforeWrite(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Use(SecureHeaders(DefaultSecurityOptions()))
+	app.Get("/foo", func(ctx *Context) {
+		ctx.HTML(ctx.CSPNonce())
+		ctx.Response.Headers = map[string]string{"X-Frame-Options": "SAMEORIGIN"}
+	})
+
+	ctx := testContext(Request{Method: "GET", URL: "/foo"})
+	app.dispatch(ctx)
+	ctx.prepareResponse()
+
+	headers := ctx.Response.Headers
+	nonce := ctx.Response.Body
+
+	if nonce == "" || !strings.Contains(headers["Content-Security-Policy"], "'nonce-"+nonce+"'") {
+		t.Errorf("CSP should contain request nonce")
+	}
+
+	if headers["X-Frame-Options"] != "SAMEORIGIN" {
+		t.Errorf("Header set by closure should be kept")
+	}
+
+	if headers["X-Content-Type-Options"] != "nosniff" || headers["Referrer-Policy"] == "" || headers["Permissions-Policy"] == "" {
+		t.Errorf("Security headers should be added even if closure replaced headers")
+	}
+
+	if _, ok := headers["Strict-Transport-Security"]; ok {
+		t.Errorf("HSTS should not be sent over plain HTTP")
+	}
+}
+
+func TestSecureHeadersHSTSAndDisabledHeaders(t *testing.T) {
+	options := DefaultSecurityOptions()
+	options.ContentSecurityPolicy = ""
+
+	app := Create(DefaultConfig())
+	app.Use(SecureHeaders(options))
+
+	ctx := testContext(Request{Method: "GET", URL: "/foo"})
+	ctx.tlsState = &tls.ConnectionState{}
+	app.dispatch(ctx)
+	ctx.prepareResponse()
+
+	if ctx.Response.Headers["Strict-Transport-Security"] == "" {
+		t.Errorf("HSTS should be sent over HTTPS")
+	}
+
+	if _, ok := ctx.Response.Headers["Content-Security-Policy"]; ok || ctx.CSPNonce() != "" {
+		t.Errorf("Disabled header should not be sent")
+	}
+}
+
+func TestSecureHeadersOnWire(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Use(SecureHeaders(DefaultSecurityOptions()))
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /missing HTTP/1.1\r\n\r\n"))
+	data, _ := ioutil.ReadAll(client)
+
+	if !strings.Contains(string(data), "X-Content-Type-Options: nosniff\r\n") {
+		t.Errorf("Security headers should be sent with every response")
+	}
+}
+
+func TestSecureHeadersNonceFailure(t *testing.T) {
+	failRandom(t)
+
+	app := Create(DefaultConfig())
+	app.Use(SecureHeaders(DefaultSecurityOptions()))
+	app.Get("/foo", func(ctx *Context) {
+		t.Errorf("Closure shouldn't be called without nonce")
+	})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/foo"})
+	if ctx.Response.Status != 500 || strings.Contains(ctx.Response.Headers["Content-Security-Policy"], "AAAA") {
+		t.Errorf("Request should fail when nonce can't be generated")
+	}
+}
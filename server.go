@@ -0,0 +1,163 @@
+package banjo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// ErrServerClosed is returned by Serve & Run after Shutdown call
+var ErrServerClosed = errors.New("banjo: server closed")
+
+// serverState struct
+//
+// Runtime state shared by all copies of Banjo struct
+//
+type serverState struct {
+	active   int64
+	queued   int64
+	accepted uint64
+	rejected uint64
+
+	mutex     sync.Mutex
+	listeners map[net.Listener]struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	slots   chan struct{}
+	work    chan net.Conn
+	workers sync.Once
+}
+
+// newServerState function
+//
+// Returns server state with base context derived
+// from Config.BaseContext & connection limits
+//
+// Params:
+// - config {Config}
+//
+// Response:
+// - state {*serverState}
+//
+func newServerState(config Config) *serverState {
+	parent := config.BaseContext
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+
+	state := &serverState{
+		listeners: make(map[net.Listener]struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	limit := config.MaxConnections
+	if config.Workers > 0 && (limit <= 0 || config.Workers < limit) {
+		limit = config.Workers
+	}
+
+	if limit > 0 {
+		state.slots = make(chan struct{}, limit)
+	}
+
+	if config.Workers > 0 {
+		state.work = make(chan net.Conn)
+	}
+
+	return state
+}
+
+// Shutdown function
+//
+// Closes all listeners & cancels contexts of requests in progress
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (banjo Banjo) Shutdown() {
+	if banjo.server == nil {
+		return
+	}
+
+	banjo.server.cancel()
+
+	banjo.server.mutex.Lock()
+	defer banjo.server.mutex.Unlock()
+
+	for listener := range banjo.server.listeners {
+		listener.Close()
+	}
+}
+
+// baseContext function
+//
+// Returns server context, all request contexts are derived from it
+//
+// Params:
+// - None
+//
+// Response:
+// - ctx {context.Context}
+//
+func (banjo Banjo) baseContext() context.Context {
+	if banjo.server == nil {
+		return context.Background()
+	}
+
+	return banjo.server.ctx
+}
+
+// track function
+//
+// Registers listener for Shutdown
+//
+// Params:
+// - listener {net.Listener}
+//
+// Response:
+// - ok {bool} false if server is already shut down
+//
+func (banjo Banjo) track(listener net.Listener) bool {
+	if banjo.server == nil {
+		return true
+	}
+
+	banjo.server.mutex.Lock()
+	defer banjo.server.mutex.Unlock()
+
+	if banjo.server.ctx.Err() != nil {
+		return false
+	}
+
+	banjo.server.listeners[listener] = struct{}{}
+
+	return true
+}
+
+// untrack function
+//
+// Removes listener registered with track
+//
+// Params:
+// - listener {net.Listener}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) untrack(listener net.Listener) {
+	if banjo.server == nil {
+		return
+	}
+
+	banjo.server.mutex.Lock()
+	defer banjo.server.mutex.Unlock()
+
+	delete(banjo.server.listeners, listener)
+}
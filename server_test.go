@@ -0,0 +1,59 @@
+package banjo
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestShutdownCancelsRequests(t *testing.T) {
+	app := Create(DefaultConfig())
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	app.Get("/slow", func(ctx *Context) {
+		close(started)
+		<-ctx.Context().Done()
+		close(cancelled)
+	})
+
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listener should be created")
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Serve(server)
+	}()
+
+	conn, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatalf("Connection should be established")
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("GET /slow HTTP/1.1\r\n\r\n"))
+	<-started
+
+	app.Shutdown()
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Request context should be cancelled on shutdown")
+	}
+
+	select {
+	case err := <-served:
+		if err != ErrServerClosed {
+			t.Errorf("Serve should return ErrServerClosed")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Serve should return after shutdown")
+	}
+
+	if app.Serve(server) != ErrServerClosed {
+		t.Errorf("Serve should fail after shutdown")
+	}
+}
@@ -0,0 +1,336 @@
+package banjo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatInterval is default interval between
+// heartbeat comments sent to the Server-Sent Events client
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// ErrStreamClosed is returned by EventStream
+// when client closed the connection
+var ErrStreamClosed = errors.New("event stream closed")
+
+// Event struct
+//
+// Single Server-Sent Event,
+// empty fields are not sent to the client
+//
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// EventStream struct
+//
+// Opened Server-Sent Events connection,
+// passed to ctx.SSE callback
+//
+type EventStream struct {
+	LastEventID string
+
+	mutex     sync.Mutex
+	writer    *ResponseWriter
+	done      chan struct{}
+	closeOnce sync.Once
+	heartbeat chan time.Duration
+	beating   sync.WaitGroup
+}
+
+// SSE function
+//
+// Opens Server-Sent Events stream and passes it to the callback,
+// connection stays open until callback returns
+// Example usage:
+// ctx.SSE(func(stream *banjo.EventStream) {
+//   for {
+//     select {
+//     case <-stream.Done():
+//       return
+//     case msg := <-updates:
+//       stream.Send(banjo.Event{Event: "update", Data: msg})
+//     }
+//   }
+// })
+//
+// Params:
+// - closure {func(stream *EventStream)} Closure for sending events
+//
+// Response:
+// - None
+//
+func (ctx *Context) SSE(closure func(stream *EventStream)) {
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	ctx.Response.Headers["Content-Type"] = "text/event-stream"
+	ctx.Response.Headers["Cache-Control"] = "no-cache"
+	if ctx.Response.Status == 0 {
+		ctx.Response.Status = 200
+	}
+
+	ctx.clearDeadlines()
+
+	stream := &EventStream{
+		LastEventID: ctx.Request.Headers["Last-Event-ID"],
+		writer:      ctx.Writer(),
+		done:        make(chan struct{}),
+		heartbeat:   make(chan time.Duration, 1),
+	}
+
+	if err := stream.flush(); err != nil {
+		return
+	}
+
+	go stream.watch(ctx)
+
+	if ctx.conn != nil {
+		stream.beating.Add(1)
+
+		go stream.beat(DefaultHeartbeatInterval)
+	}
+
+	defer stream.stop()
+
+	closure(stream)
+}
+
+// Send function
+//
+// Sends event to the client
+//
+// Params:
+// - event {Event}
+//
+// Response:
+// - err {error} ErrStreamClosed if client disconnected
+//
+func (stream *EventStream) Send(event Event) error {
+	var buffer bytes.Buffer
+
+	if event.ID != "" {
+		buffer.WriteString("id: " + sanitizeEventField(event.ID) + "\n")
+	}
+
+	if event.Event != "" {
+		buffer.WriteString("event: " + sanitizeEventField(event.Event) + "\n")
+	}
+
+	if event.Retry > 0 {
+		buffer.WriteString("retry: " + strconv.FormatInt(int64(event.Retry/time.Millisecond), 10) + "\n")
+	}
+
+	for _, line := range strings.Split(strings.Replace(event.Data, "\r\n", "\n", -1), "\n") {
+		buffer.WriteString("data: " + line + "\n")
+	}
+
+	buffer.WriteString("\n")
+
+	return stream.write(buffer.Bytes())
+}
+
+// Heartbeat function
+//
+// Changes interval between heartbeat comments,
+// zero or negative interval disables heartbeats
+//
+// Params:
+// - interval {time.Duration}
+//
+// Response:
+// - None
+//
+func (stream *EventStream) Heartbeat(interval time.Duration) {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	select {
+	case <-stream.heartbeat:
+	default:
+	}
+
+	select {
+	case stream.heartbeat <- interval:
+	default:
+	}
+}
+
+// Done function
+//
+// Returns channel which is closed when client disconnects
+//
+// Params:
+// - None
+//
+// Response:
+// - done {<-chan struct{}}
+//
+func (stream *EventStream) Done() <-chan struct{} {
+	return stream.done
+}
+
+// write function
+//
+// Writes raw data to the stream and flushes it
+//
+// Params:
+// - data {[]byte}
+//
+// Response:
+// - err {error}
+//
+func (stream *EventStream) write(data []byte) error {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	select {
+	case <-stream.done:
+		return ErrStreamClosed
+	default:
+	}
+
+	if _, err := stream.writer.Write(data); err != nil {
+		stream.close()
+		return ErrStreamClosed
+	}
+
+	return stream.flush()
+}
+
+// flush function
+//
+// Sends buffered data to the client
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error}
+//
+func (stream *EventStream) flush() error {
+	if err := stream.writer.Flush(); err != nil {
+		stream.close()
+		return ErrStreamClosed
+	}
+
+	return nil
+}
+
+// close function
+//
+// Marks stream as closed
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (stream *EventStream) close() {
+	stream.closeOnce.Do(func() {
+		close(stream.done)
+	})
+}
+
+// stop function
+//
+// Closes stream & waits until heartbeats and
+// in-flight writes are finished, so nothing is written
+// to the connection after callback returns
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (stream *EventStream) stop() {
+	stream.close()
+	stream.beating.Wait()
+
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+}
+
+// watch function
+//
+// Closes stream when request context is cancelled,
+// e.g. when client disconnects or server shuts down
+//
+// Params:
+// - ctx {*Context}
+//
+// Response:
+// - None
+//
+func (stream *EventStream) watch(ctx *Context) {
+	select {
+	case <-ctx.Context().Done():
+		stream.close()
+	case <-stream.done:
+	}
+}
+
+// beat function
+//
+// Sends heartbeat comments to keep connection alive
+// through proxies until stream is closed
+//
+// Params:
+// - interval {time.Duration}
+//
+// Response:
+// - None
+//
+func (stream *EventStream) beat(interval time.Duration) {
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+
+	defer stream.beating.Done()
+
+	for {
+		if ticker == nil && interval > 0 {
+			ticker = time.NewTicker(interval)
+			tick = ticker.C
+		}
+
+		select {
+		case <-stream.done:
+			if ticker != nil {
+				ticker.Stop()
+			}
+			return
+		case interval = <-stream.heartbeat:
+			if ticker != nil {
+				ticker.Stop()
+				ticker, tick = nil, nil
+			}
+		case <-tick:
+			stream.write([]byte(fmt.Sprintf(": heartbeat %d\n\n", time.Now().Unix())))
+		}
+	}
+}
+
+// sanitizeEventField function
+//
+// Removes line breaks from single line event fields
+//
+// Params:
+// - value {string}
+//
+// Response:
+// - value {string}
+//
+func sanitizeEventField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,126 @@
+package banjo
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEEventFormatting(t *testing.T) {
+	ctx := &Context{}
+	ctx.SSE(func(stream *EventStream) {
+		stream.Send(Event{ID: "1", Event: "update", Data: "foo\nbar", Retry: 3 * time.Second})
+	})
+
+	if ctx.Response.Headers["Content-Type"] != "text/event-stream" {
+		t.Errorf("Content-Type should be text/event-stream")
+	}
+
+	if ctx.Response.Body != "id: 1\nevent: update\nretry: 3000\ndata: foo\ndata: bar\n\n" {
+		t.Errorf("Event should be formatted by SSE rules")
+	}
+}
+
+func TestSSEKeepsStatusSetByHandler(t *testing.T) {
+	ctx := &Context{}
+	ctx.Response.Status = 204
+	ctx.SSE(func(stream *EventStream) {})
+
+	if ctx.Response.Status != 204 {
+		t.Errorf("Status should be 204")
+	}
+}
+
+func TestSSEHeartbeatDoesNotBlock(t *testing.T) {
+	ctx := &Context{}
+	ctx.SSE(func(stream *EventStream) {
+		stream.Heartbeat(time.Second)
+		stream.Heartbeat(time.Minute)
+		stream.Heartbeat(0)
+	})
+}
+
+func TestSSEStopsOnClientDisconnect(t *testing.T) {
+	app := Create(DefaultConfig())
+	lastEventID := make(chan string, 1)
+	finished := make(chan error, 1)
+
+	app.Get("/events", func(ctx *Context) {
+		ctx.SSE(func(stream *EventStream) {
+			lastEventID <- stream.LastEventID
+			stream.Send(Event{ID: "6", Data: "hello"})
+			<-stream.Done()
+			finished <- stream.Send(Event{Data: "late"})
+		})
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /events HTTP/1.1\r\nLast-Event-ID: 5\r\n\r\n"))
+
+	reader := bufio.NewReader(client)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Event should be received")
+		}
+		if strings.Contains(line, "data: hello") {
+			break
+		}
+	}
+
+	if id := <-lastEventID; id != "5" {
+		t.Errorf("LastEventID should be 5")
+	}
+
+	client.Close()
+
+	select {
+	case err := <-finished:
+		if err != ErrStreamClosed {
+			t.Errorf("Send should return ErrStreamClosed after disconnect")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Stream should be closed after disconnect")
+	}
+}
+
+func TestSSENotCompressed(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Use(Compress(CompressOptions{MinSize: 1}))
+
+	sent := make(chan struct{})
+	app.Get("/events", func(ctx *Context) {
+		ctx.SSE(func(stream *EventStream) {
+			stream.Send(Event{Data: "hello"})
+			<-sent
+		})
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+	defer close(sent)
+
+	client.Write([]byte("GET /events HTTP/1.1\r\nAccept-Encoding: gzip\r\n\r\n"))
+
+	reader := bufio.NewReader(client)
+	head := ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Event should be delivered before stream ends")
+		}
+
+		head += line
+		if strings.Contains(line, "data: hello") {
+			break
+		}
+	}
+
+	if strings.Contains(head, "Content-Encoding") {
+		t.Errorf("Event stream shouldn't be compressed")
+	}
+}
@@ -0,0 +1,553 @@
+package banjo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultStaticIndex is default name of directory index file
+const DefaultStaticIndex = "index.html"
+
+// errRangeNotSatisfiable is returned by parseRange
+// when range starts after the end of file
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// StaticOptions struct
+//
+// Static files configuration
+//
+type StaticOptions struct {
+	// Index is file served for directory, DefaultStaticIndex if empty
+	Index string
+
+	// Browse enables listing of directories without index file
+	Browse bool
+
+	// Precompressed serves "name.gz" instead of "name"
+	// to clients accepting gzip when it exists
+	Precompressed bool
+
+	// AllowHidden serves files & directories starting with ".",
+	// they are answered with 404 by default
+	AllowHidden bool
+
+	// CacheControl is Cache-Control header by file extension,
+	// e.g. ".css", "*" is used for other extensions
+	CacheControl map[string]string
+}
+
+// Static function
+//
+// Serves files from directory under given prefix
+// Example usage:
+// app.Static("/assets", "./public")
+//
+// Params:
+// - prefix  {string} URL prefix
+// - dir     {string} directory path
+// - options {...StaticOptions} optional configuration
+//
+// Response:
+// - None
+//
+func (banjo Banjo) Static(prefix string, dir string, options ...StaticOptions) {
+	banjo.StaticFS(prefix, os.DirFS(dir), options...)
+}
+
+// StaticFS function
+//
+// Serves files from file system under given prefix,
+// GET & HEAD requests are supported, Range, If-Range & conditional
+// headers are honoured with Last-Modified & ETag of the file
+// Example usage:
+// //go:embed public
+// var public embed.FS
+// ...
+// assets, _ := fs.Sub(public, "public")
+// app.StaticFS("/assets", assets)
+//
+// Params:
+// - prefix  {string} URL prefix
+// - fsys    {fs.FS}
+// - options {...StaticOptions} optional configuration
+//
+// Response:
+// - None
+//
+func (banjo Banjo) StaticFS(prefix string, fsys fs.FS, options ...StaticOptions) {
+	var config StaticOptions
+
+	if len(options) > 0 {
+		config = options[0]
+	}
+
+	if config.Index == "" {
+		config.Index = DefaultStaticIndex
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	banjo.routes.Mount(prefix, config.handler(prefix, fsys, banjo.logger))
+}
+
+// handler function
+//
+// Returns closure serving files from fsys
+//
+// Params:
+// - prefix {string} URL prefix without trailing slash
+// - fsys   {fs.FS}
+// - logger {Logger} logs failed transfers
+//
+// Response:
+// - closure {func(ctx *Context)}
+//
+func (options StaticOptions) handler(prefix string, fsys fs.FS, logger Logger) func(ctx *Context) {
+	return func(ctx *Context) {
+		switch ctx.Request.Method {
+		case "GET", "HEAD":
+		case "OPTIONS":
+			allowMethods([]string{"GET", "HEAD"})(ctx)
+			return
+		default:
+			allowMethods([]string{"GET", "HEAD"})(ctx)
+			ctx.Response.Status = 405
+			ctx.Response.Body = "Method Not Allowed"
+			return
+		}
+
+		urlPath, query := ctx.Request.URL, ""
+		if index := strings.IndexAny(urlPath, "?#"); index >= 0 {
+			urlPath, query = urlPath[:index], urlPath[index:]
+		}
+
+		name, ok := options.fileName(strings.TrimPrefix(urlPath, prefix))
+		if !ok {
+			notFound()(ctx)
+			return
+		}
+
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			staticError(ctx, err)
+			return
+		}
+
+		if !info.IsDir() {
+			options.serveFile(ctx, fsys, name, info, logger)
+			return
+		}
+
+		if !strings.HasSuffix(urlPath, "/") {
+			ctx.RedirectTo(urlPath + "/" + query)
+			return
+		}
+
+		index := path.Join(name, options.Index)
+		if info, err := fs.Stat(fsys, index); err == nil && !info.IsDir() {
+			options.serveFile(ctx, fsys, index, info, logger)
+			return
+		}
+
+		if !options.Browse {
+			notFound()(ctx)
+			return
+		}
+
+		options.listDir(ctx, fsys, name)
+	}
+}
+
+// fileName function
+//
+// Converts URL path to fs.FS name, ".." segments can't
+// leave the root, hidden names are rejected unless allowed
+//
+// Params:
+// - urlPath {string} URL path without prefix & query
+//
+// Response:
+// - name {string} valid fs.FS path
+// - ok   {bool}   false if path can't be served
+//
+func (options StaticOptions) fileName(urlPath string) (string, bool) {
+	unescaped, err := url.PathUnescape(urlPath)
+	if err != nil || strings.ContainsAny(unescaped, "\x00\\") {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+unescaped), "/")
+	if name == "" {
+		return ".", true
+	}
+
+	if !fs.ValidPath(name) {
+		return "", false
+	}
+
+	if !options.AllowHidden {
+		for _, segment := range strings.Split(name, "/") {
+			if strings.HasPrefix(segment, ".") {
+				return "", false
+			}
+		}
+	}
+
+	return name, true
+}
+
+// serveFile function
+//
+// Streams file or its precompressed variant with
+// validators, caching headers & Range support
+//
+// Params:
+// - ctx    {*Context}
+// - fsys   {fs.FS}
+// - name   {string}      file name
+// - info   {fs.FileInfo} file info
+// - logger {Logger}
+//
+// Response:
+// - None
+//
+func (options StaticOptions) serveFile(ctx *Context, fsys fs.FS, name string, info fs.FileInfo, logger Logger) {
+	if ctx.Response.Headers == nil {
+		ctx.Response.Headers = make(map[string]string)
+	}
+
+	headers := ctx.Response.Headers
+	encoding := ""
+
+	if options.Precompressed {
+		if gz, err := fs.Stat(fsys, name+".gz"); err == nil && !gz.IsDir() {
+			addVary(headers, "Accept-Encoding")
+
+			if negotiateEncoding(ctx.Request.Header("Accept-Encoding")) == "gzip" {
+				encoding, info = "gzip", gz
+			}
+		}
+	}
+
+	fileName := name
+	if encoding != "" {
+		fileName += ".gz"
+	}
+
+	file, err := fsys.Open(fileName)
+	if err != nil {
+		staticError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" && encoding == "" {
+		reader, contentType = sniffContentType(file)
+	} else if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	headers["Content-Type"] = contentType
+	headers["Accept-Ranges"] = "bytes"
+
+	if encoding != "" {
+		headers["Content-Encoding"] = encoding
+	}
+
+	if cacheControl := options.cacheControl(name); cacheControl != "" {
+		headers["Cache-Control"] = cacheControl
+	}
+
+	ctx.SetLastModified(info.ModTime())
+	ctx.SetETag(fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size()))
+
+	if !ctx.CheckPreconditions() {
+		return
+	}
+
+	size := info.Size()
+	start, length := int64(0), size
+	ctx.Response.Status = 200
+
+	if header := ctx.Request.Header("Range"); header != "" && rangeFresh(ctx.Request.Header("If-Range"), headers) {
+		first, count, ok, err := parseRange(header, size)
+
+		if err != nil {
+			delete(headers, "Content-Encoding")
+			headers["Content-Type"] = "text/plain"
+			headers["Content-Range"] = "bytes */" + strconv.FormatInt(size, 10)
+			ctx.Response.Status = 416
+			ctx.Response.Body = "Range Not Satisfiable"
+			return
+		}
+
+		if ok {
+			start, length = first, count
+			headers["Content-Range"] = fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size)
+			ctx.Response.Status = 206
+		}
+	}
+
+	headers["Content-Length"] = strconv.FormatInt(length, 10)
+
+	if ctx.Request.Method == "HEAD" {
+		ctx.Writer().Flush()
+		return
+	}
+
+	if err := skipBytes(reader, start); err != nil {
+		staticError(ctx, err)
+		return
+	}
+
+	if err := copyStream(ctx.Writer(), io.LimitReader(reader, length)); err != nil {
+		str := fmt.Sprintf("Error while serving static file %s:\nError: %v", name, err)
+		logger.Error(str)
+	}
+}
+
+// listDir function
+//
+// Writes HTML listing of directory
+//
+// Params:
+// - ctx  {*Context}
+// - fsys {fs.FS}
+// - name {string} directory name
+//
+// Response:
+// - None
+//
+func (options StaticOptions) listDir(ctx *Context, fsys fs.FS, name string) {
+	entries, err := fs.ReadDir(fsys, name)
+	if err != nil {
+		staticError(ctx, err)
+		return
+	}
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString("<pre>\n")
+
+	for _, entry := range entries {
+		entryName := entry.Name()
+
+		if !options.AllowHidden && strings.HasPrefix(entryName, ".") {
+			continue
+		}
+
+		if entry.IsDir() {
+			entryName += "/"
+		}
+
+		link := url.URL{Path: entryName}
+		buffer.WriteString(fmt.Sprintf("<a href=\"%s\">%s</a>\n", link.EscapedPath(), html.EscapeString(entryName)))
+	}
+
+	buffer.WriteString("</pre>\n")
+
+	ctx.HTML(buffer.String())
+	ctx.Response.Headers["Content-Type"] = "text/html; charset=utf-8"
+}
+
+// cacheControl function
+//
+// Returns Cache-Control value for file extension
+//
+// Params:
+// - name {string} file name
+//
+// Response:
+// - value {string} empty if not configured
+//
+func (options StaticOptions) cacheControl(name string) string {
+	if value, ok := options.CacheControl[strings.ToLower(path.Ext(name))]; ok {
+		return value
+	}
+
+	return options.CacheControl["*"]
+}
+
+// parseRange function
+//
+// Parses single byte range of Range header,
+// multiple ranges & invalid headers are ignored
+//
+// Params:
+// - header {string} Range header value
+// - size   {int64}  file size
+//
+// Response:
+// - start  {int64}
+// - length {int64}
+// - ok     {bool}  false if range should be ignored
+// - err    {error} range isn't satisfiable
+//
+func parseRange(header string, size int64) (int64, int64, bool, error) {
+	spec := strings.TrimSpace(header)
+	if !strings.HasPrefix(spec, "bytes=") || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+
+	bounds := strings.SplitN(strings.TrimSpace(spec[len("bytes="):]), "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, false, nil
+	}
+
+	first, last := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+
+	if first == "" {
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, 0, false, nil
+		}
+
+		if suffix == 0 || size == 0 {
+			return 0, 0, false, errRangeNotSatisfiable
+		}
+
+		if suffix > size {
+			suffix = size
+		}
+
+		return size - suffix, suffix, true, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, nil
+	}
+
+	end := size - 1
+
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, false, nil
+		}
+	}
+
+	if start >= size {
+		return 0, 0, false, errRangeNotSatisfiable
+	}
+
+	if end >= size {
+		end = size - 1
+	}
+
+	return start, end - start + 1, true, nil
+}
+
+// rangeFresh function
+//
+// Checks If-Range against ETag or Last-Modified,
+// Range is ignored when representation changed
+//
+// Params:
+// - ifRange {string} If-Range header value
+// - headers {map[string]string} response headers
+//
+// Response:
+// - ok {bool}
+//
+func rangeFresh(ifRange string, headers map[string]string) bool {
+	ifRange = strings.TrimSpace(ifRange)
+
+	if ifRange == "" {
+		return true
+	}
+
+	if strings.HasSuffix(ifRange, `"`) {
+		return matchETag(ifRange, headers["ETag"], false)
+	}
+
+	return ifRange == headers["Last-Modified"]
+}
+
+// sniffContentType function
+//
+// Detects content type from first 512 bytes of file
+//
+// Params:
+// - file {fs.File}
+//
+// Response:
+// - reader      {io.Reader} reader starting at the beginning of file
+// - contentType {string}
+//
+func sniffContentType(file fs.File) (io.Reader, string) {
+	data := make([]byte, 512)
+	n, _ := io.ReadFull(file, data)
+	data = data[:n]
+
+	if seeker, ok := file.(io.Seeker); ok {
+		if _, err := seeker.Seek(0, io.SeekStart); err == nil {
+			return file, http.DetectContentType(data)
+		}
+	}
+
+	return io.MultiReader(bytes.NewReader(data), file), http.DetectContentType(data)
+}
+
+// skipBytes function
+//
+// Moves reader to offset, seeking when possible
+//
+// Params:
+// - reader {io.Reader}
+// - offset {int64}
+//
+// Response:
+// - err {error}
+//
+func skipBytes(reader io.Reader, offset int64) error {
+	if offset == 0 {
+		return nil
+	}
+
+	if seeker, ok := reader.(io.Seeker); ok {
+		_, err := seeker.Seek(offset, io.SeekStart)
+		return err
+	}
+
+	_, err := io.CopyN(ioutil.Discard, reader, offset)
+
+	return err
+}
+
+// staticError function
+//
+// Answers request with 404, 403 or 500
+// depending on file system error
+//
+// Params:
+// - ctx {*Context}
+// - err {error}
+//
+// Response:
+// - None
+//
+func staticError(ctx *Context, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		notFound()(ctx)
+	case errors.Is(err, fs.ErrPermission):
+		ctx.Response.Status = 403
+		ctx.Response.Body = "Forbidden"
+	default:
+		ctx.InternalServerError()
+	}
+}
@@ -0,0 +1,173 @@
+package banjo
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var staticModified = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func staticApp(options StaticOptions) Banjo {
+	files := fstest.MapFS{
+		"index.html":        {Data: []byte("<h1>home</h1>"), ModTime: staticModified},
+		"css/app.css":       {Data: []byte("body{color:red}"), ModTime: staticModified},
+		"css/app.css.gz":    {Data: []byte("gzipped"), ModTime: staticModified},
+		"docs/a b.txt":      {Data: []byte("0123456789"), ModTime: staticModified},
+		"docs/<script>.txt": {Data: []byte("x"), ModTime: staticModified},
+		"noext":             {Data: []byte("%PDF-1.4 ..."), ModTime: staticModified},
+		".env":              {Data: []byte("SECRET=1"), ModTime: staticModified},
+	}
+
+	app := Create(DefaultConfig())
+	app.StaticFS("/assets/", files, options)
+
+	return app
+}
+
+func TestStaticFiles(t *testing.T) {
+	app := staticApp(StaticOptions{CacheControl: map[string]string{".css": "public, max-age=31536000", "*": "no-cache"}})
+
+	cases := []struct {
+		url         string
+		status      int
+		body        string
+		contentType string
+	}{
+		{"/assets/", 200, "<h1>home</h1>", "text/html; charset=utf-8"},
+		{"/assets/css/app.css?v=1", 200, "body{color:red}", "text/css; charset=utf-8"},
+		{"/assets/docs/a%20b.txt", 200, "0123456789", "text/plain; charset=utf-8"},
+		{"/assets/noext", 200, "%PDF-1.4 ...", "application/pdf"},
+		{"/assets/../../css/../index.html", 200, "<h1>home</h1>", "text/html; charset=utf-8"},
+		{"/assets/%2e%2e/static.go", 404, "Page Not Found", ""},
+		{"/assets/..%5cstatic.go", 404, "Page Not Found", ""},
+		{"/assets/.env", 404, "Page Not Found", ""},
+		{"/assets/docs/", 404, "Page Not Found", ""},
+		{"/assets/missing.js", 404, "Page Not Found", ""},
+	}
+
+	for _, c := range cases {
+		ctx := testDispatch(app, Request{Method: "GET", URL: c.url})
+
+		if ctx.Response.Status != c.status || ctx.Response.Body != c.body {
+			t.Errorf("%s should be answered with %d %q, got %d %q", c.url, c.status, c.body, ctx.Response.Status, ctx.Response.Body)
+		}
+
+		if c.contentType != "" && ctx.Response.Headers["Content-Type"] != c.contentType {
+			t.Errorf("%s should have Content-Type %q, got %q", c.url, c.contentType, ctx.Response.Headers["Content-Type"])
+		}
+	}
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/assets/css/app.css"})
+	if ctx.Response.Headers["Cache-Control"] != "public, max-age=31536000" || ctx.Response.Headers["Last-Modified"] != "Thu, 02 Jan 2020 03:04:05 GMT" {
+		t.Errorf("Caching headers should be set by extension")
+	}
+
+	if ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/index.html"}); ctx.Response.Headers["Cache-Control"] != "no-cache" {
+		t.Errorf("Default Cache-Control should be used for other extensions")
+	}
+
+	if ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/css?x=1"}); ctx.Response.Status != 301 || ctx.Response.Headers["Location"] != "/assets/css/?x=1" {
+		t.Errorf("Directory without trailing slash should be redirected")
+	}
+
+	if ctx = testDispatch(app, Request{Method: "POST", URL: "/assets/index.html"}); ctx.Response.Status != 405 || ctx.Response.Headers["Allow"] != "GET, HEAD, OPTIONS" {
+		t.Errorf("POST should be answered with 405")
+	}
+}
+
+func TestStaticConditionalAndRange(t *testing.T) {
+	app := staticApp(StaticOptions{})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/assets/docs/a%20b.txt"})
+	etag := ctx.Response.Headers["ETag"]
+
+	if ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/docs/a%20b.txt", Headers: map[string]string{"If-None-Match": etag}}); ctx.Response.Status != 304 || ctx.Response.Body != "" {
+		t.Errorf("Matching If-None-Match should be answered with 304")
+	}
+
+	if ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/docs/a%20b.txt", Headers: map[string]string{"If-Modified-Since": "Thu, 02 Jan 2020 03:04:05 GMT"}}); ctx.Response.Status != 304 {
+		t.Errorf("Unmodified file should be answered with 304")
+	}
+
+	cases := []struct {
+		header       string
+		ifRange      string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"bytes=2-4", "", 206, "234", "bytes 2-4/10"},
+		{"bytes=7-", "", 206, "789", "bytes 7-9/10"},
+		{"bytes=-2", "", 206, "89", "bytes 8-9/10"},
+		{"bytes=5-100", "", 206, "56789", "bytes 5-9/10"},
+		{"bytes=10-", "", 416, "Range Not Satisfiable", "bytes */10"},
+		{"bytes=0-1,4-5", "", 200, "0123456789", ""},
+		{"lines=1-2", "", 200, "0123456789", ""},
+		{"bytes=2-4", etag, 206, "234", "bytes 2-4/10"},
+		{"bytes=2-4", `"stale"`, 200, "0123456789", ""},
+	}
+
+	for _, c := range cases {
+		ctx := testDispatch(app, Request{Method: "GET", URL: "/assets/docs/a%20b.txt", Headers: map[string]string{"Range": c.header, "If-Range": c.ifRange}})
+
+		if ctx.Response.Status != c.status || ctx.Response.Body != c.body || ctx.Response.Headers["Content-Range"] != c.contentRange {
+			t.Errorf("Range %q should be answered with %d %q, got %d %q", c.header, c.status, c.body, ctx.Response.Status, ctx.Response.Body)
+		}
+	}
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/docs/a%20b.txt", Headers: map[string]string{"range": "bytes=2-4", "if-range": `"stale"`}})
+	if ctx.Response.Status != 200 {
+		t.Errorf("Lower case If-Range should be checked, got %d", ctx.Response.Status)
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/docs/a%20b.txt", Headers: map[string]string{"range": "bytes=2-4"}})
+	if ctx.Response.Status != 206 || ctx.Response.Body != "234" {
+		t.Errorf("Lower case Range should be served, got %d", ctx.Response.Status)
+	}
+}
+
+func TestStaticPrecompressedAndBrowse(t *testing.T) {
+	app := staticApp(StaticOptions{Precompressed: true, Browse: true, Index: "missing.html"})
+
+	ctx := testDispatch(app, Request{Method: "GET", URL: "/assets/css/app.css", Headers: map[string]string{"Accept-Encoding": "gzip"}})
+	if ctx.Response.Body != "gzipped" || ctx.Response.Headers["Content-Encoding"] != "gzip" || ctx.Response.Headers["Content-Type"] != "text/css; charset=utf-8" {
+		t.Errorf("Precompressed variant should be served to gzip clients")
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/css/app.css"})
+	if ctx.Response.Body != "body{color:red}" || ctx.Response.Headers["Vary"] != "Accept-Encoding" {
+		t.Errorf("Original file should be served to other clients")
+	}
+
+	ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/docs/"})
+	expected := "<pre>\n<a href=\"%3Cscript%3E.txt\">&lt;script&gt;.txt</a>\n<a href=\"a%20b.txt\">a b.txt</a>\n</pre>\n"
+	if ctx.Response.Body != expected {
+		t.Errorf("Directory listing should be escaped, got %q", ctx.Response.Body)
+	}
+
+	if ctx = testDispatch(app, Request{Method: "GET", URL: "/assets/"}); ctx.Response.Status != 200 || ctx.Response.Body == "" {
+		t.Errorf("Root directory should be listed")
+	}
+}
+
+func TestStaticHeadOverConnection(t *testing.T) {
+	app := staticApp(StaticOptions{})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("HEAD /assets/docs/a%20b.txt HTTP/1.1\r\n\r\n"))
+
+	request, _ := http.NewRequest("HEAD", "/", nil)
+	response, err := http.ReadResponse(bufio.NewReader(client), request)
+	if err != nil {
+		t.Fatalf("Response should be read: %v", err)
+	}
+
+	if response.StatusCode != 200 || response.ContentLength != 10 {
+		t.Errorf("HEAD should be answered with file length, got %d %d", response.StatusCode, response.ContentLength)
+	}
+}
@@ -0,0 +1,142 @@
+package banjo
+
+import (
+	"fmt"
+	"sync"
+)
+
+// valueStore struct
+//
+// Request scoped key/value store shared by
+// middleware, closure & goroutines started by them
+//
+type valueStore struct {
+	mutex  sync.RWMutex
+	values map[string]interface{}
+}
+
+// Set function
+//
+// Stores value for the current request
+// Example usage:
+// ctx.Set("user", user)
+//
+// Params:
+// - key   {string}
+// - value {interface{}}
+//
+// Response:
+// - None
+//
+func (ctx *Context) Set(key string, value interface{}) {
+	store := ctx.values
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.values[key] = value
+}
+
+// Get function
+//
+// Returns value stored with Set
+//
+// Params:
+// - key {string}
+//
+// Response:
+// - value {interface{}}
+// - ok    {bool} false if value wasn't set
+//
+func (ctx *Context) Get(key string) (interface{}, bool) {
+	store := ctx.values
+	if store == nil {
+		return nil, false
+	}
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	value, ok := store.values[key]
+
+	return value, ok
+}
+
+// MustGet function
+//
+// Same as Get, but panics if value wasn't set
+//
+// Params:
+// - key {string}
+//
+// Response:
+// - value {interface{}}
+//
+func (ctx *Context) MustGet(key string) interface{} {
+	value, ok := ctx.Get(key)
+	if !ok {
+		panic(fmt.Sprintf("banjo: key %q does not exist", key))
+	}
+
+	return value
+}
+
+// GetAs function
+//
+// Returns value stored with Set converted to T
+// Example usage:
+// user, ok := banjo.GetAs[User](ctx, "user")
+//
+// Params:
+// - ctx {*Context}
+// - key {string}
+//
+// Response:
+// - value {T}
+// - ok    {bool} false if value wasn't set or has another type
+//
+func GetAs[T any](ctx *Context, key string) (T, bool) {
+	value, ok := ctx.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	typed, ok := value.(T)
+
+	return typed, ok
+}
+
+// MustGetAs function
+//
+// Same as GetAs, but panics if value wasn't set or has another type
+//
+// Params:
+// - ctx {*Context}
+// - key {string}
+//
+// Response:
+// - value {T}
+//
+func MustGetAs[T any](ctx *Context, key string) T {
+	value, ok := GetAs[T](ctx, key)
+	if !ok {
+		panic(fmt.Sprintf("banjo: key %q does not exist or has type %T", key, ctx.MustGet(key)))
+	}
+
+	return value
+}
+
+// newValueStore function
+//
+// Returns empty request store
+//
+// Params:
+// - None
+//
+// Response:
+// - store {*valueStore}
+//
+func newValueStore() *valueStore {
+	return &valueStore{values: make(map[string]interface{})}
+}
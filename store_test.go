@@ -0,0 +1,80 @@
+package banjo
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestContextSetGet(t *testing.T) {
+	ctx := testContext(Request{})
+	ctx.Set("user", "foo")
+
+	if value, ok := ctx.Get("user"); !ok || value != "foo" {
+		t.Errorf("Value should be stored")
+	}
+
+	if _, ok := ctx.Get("tenant"); ok {
+		t.Errorf("Missing value should not be found")
+	}
+}
+
+func TestContextMustGetPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustGet should panic for missing key")
+		}
+	}()
+
+	ctx := testContext(Request{})
+	ctx.MustGet("user")
+}
+
+func TestContextGetAs(t *testing.T) {
+	ctx := testContext(Request{})
+	ctx.Set("id", 42)
+
+	if id, ok := GetAs[int](ctx, "id"); !ok || id != 42 {
+		t.Errorf("Typed value should be returned")
+	}
+
+	if _, ok := GetAs[string](ctx, "id"); ok {
+		t.Errorf("Value with another type should not be returned")
+	}
+
+	if MustGetAs[int](ctx, "id") != 42 {
+		t.Errorf("Typed value should be returned")
+	}
+}
+
+func TestContextStoreFromGoroutines(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Use(func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			ctx.Set("tenant", "acme")
+			next(ctx)
+		}
+	})
+
+	app.Get("/foo", func(ctx *Context) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx.Set("worker", i)
+				ctx.MustGet("tenant")
+			}(i)
+		}
+
+		wg.Wait()
+		ctx.HTML(MustGetAs[string](ctx, "tenant"))
+	})
+
+	ctx := testContext(Request{Method: "GET", URL: "/foo"})
+	app.dispatch(ctx)
+
+	if ctx.Response.Body != "acme" {
+		t.Errorf("Value set by middleware should be available in closure")
+	}
+}
@@ -0,0 +1,77 @@
+package banjo
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Timeout function
+//
+// Returns middleware which limits closure execution time,
+// request context is cancelled after duration and client
+// receives given status (503 by default) instead of closure response,
+// closure response is buffered, so streaming & WebSocket
+// routes shouldn't be wrapped with Timeout
+// Example usage:
+// app.Get("/report", banjo.Chain(report, banjo.Timeout(5*time.Second, 504)))
+//
+// Params:
+// - duration {time.Duration} closure execution limit
+// - status   {int} response status after timeout, 0 for 503
+//
+// Response:
+// - middleware {Middleware}
+//
+func Timeout(duration time.Duration, status int) Middleware {
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			timeoutCtx, cancel := context.WithTimeout(ctx.Context(), duration)
+			defer cancel()
+
+			response := ctx.Response
+			response.Headers = make(map[string]string)
+			for k, v := range ctx.Response.Headers {
+				response.Headers[k] = v
+			}
+
+			shadow := &Context{
+				Request:     ctx.Request,
+				Response:    response,
+				tlsState:    ctx.tlsState,
+				httpRequest: ctx.httpRequest,
+				remote:      ctx.RemoteAddr(),
+				proxies:     ctx.proxies,
+				requestCtx:  timeoutCtx,
+				cancel:      cancel,
+				values:      ctx.values,
+				hooks:       append([]func(ctx *Context){}, ctx.hooks...),
+			}
+
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				next(shadow)
+			}()
+
+			select {
+			case <-done:
+				ctx.Response = shadow.Response
+				ctx.hooks = shadow.hooks
+			case <-timeoutCtx.Done():
+				if ctx.Response.Headers == nil {
+					ctx.Response.Headers = make(map[string]string)
+				}
+
+				ctx.Response.Headers["Content-Type"] = "text/plain"
+				ctx.Response.Body = http.StatusText(status)
+				ctx.Response.Status = status
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package banjo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutReturnsStatus(t *testing.T) {
+	ctx := &Context{}
+	cancelled := make(chan struct{})
+
+	action := Chain(func(ctx *Context) {
+		<-ctx.Context().Done()
+		close(cancelled)
+	}, Timeout(20*time.Millisecond, 504))
+
+	action(ctx)
+
+	if ctx.Response.Status != 504 || ctx.Response.Body != "Gateway Timeout" {
+		t.Errorf("Status should be 504")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf("Closure context should be cancelled after timeout")
+	}
+}
+
+func TestTimeoutKeepsFastResponse(t *testing.T) {
+	ctx := &Context{}
+
+	action := Chain(func(ctx *Context) {
+		ctx.HTML("done")
+	}, Timeout(time.Second, 0))
+
+	action(ctx)
+
+	if ctx.Response.Status != 200 || ctx.Response.Body != "done" {
+		t.Errorf("Closure response should be kept")
+	}
+}
+
+func TestTimeoutIgnoresLateHooks(t *testing.T) {
+	ctx := &Context{}
+	ctx.onHeaders(func(ctx *Context) {})
+	registered := make(chan struct{})
+
+	action := Chain(func(ctx *Context) {
+		<-ctx.Context().Done()
+		ctx.onHeaders(func(ctx *Context) {
+			ctx.Response.Headers["X-Late"] = "1"
+		})
+		close(registered)
+	}, Timeout(10*time.Millisecond, 0))
+
+	action(ctx)
+	<-registered
+	ctx.prepareResponse()
+
+	if len(ctx.hooks) != 1 || ctx.Response.Headers["X-Late"] != "" {
+		t.Errorf("Hooks registered after timeout should be ignored")
+	}
+}
+
+func TestContextWithValue(t *testing.T) {
+	type key struct{}
+
+	ctx := &Context{}
+	ctx.WithValue(key{}, "foo")
+
+	if ctx.Context().Value(key{}) != "foo" {
+		t.Errorf("Value should be available in request context")
+	}
+}
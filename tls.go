@@ -0,0 +1,392 @@
+package banjo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultTLSReloadInterval is default interval
+// between checks of certificate files for changes
+const DefaultTLSReloadInterval = 10 * time.Second
+
+// TLSCertificate struct
+//
+// Certificate & private key PEM files pair,
+// certificate is selected by SNI server name
+//
+type TLSCertificate struct {
+	CertFile string
+	KeyFile  string
+}
+
+// certificateStore struct
+//
+// Keeps loaded certificates and reloads them
+// when files are changed on disk
+//
+type certificateStore struct {
+	mutex        sync.RWMutex
+	pairs        []TLSCertificate
+	certificates []*tls.Certificate
+	modified     []time.Time
+	logger       Logger
+}
+
+// RunTLS function
+//
+// Same as Run, but serves HTTPS using given certificate,
+// certificates from Config.TLSCertificates are selected by SNI
+// and all of them are reloaded when files are changed on disk
+//
+// Params:
+// - certFile {string} certificate PEM file
+// - keyFile  {string} private key PEM file
+//
+// Response:
+// - err {error} returns error if certificates can't be loaded or listener fails
+//
+func (banjo Banjo) RunTLS(certFile string, keyFile string) error {
+	if banjo.config.ProxyProtocol && len(banjo.proxies) == 0 {
+		banjo.logger.Critical(errProxyUntrusted.Error())
+		return errProxyUntrusted
+	}
+
+	config, err := banjo.tlsConfig(TLSCertificate{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		banjo.logger.Critical(fmt.Sprintf("Error while loading TLS certificates:\nError: %v", err))
+		return err
+	}
+
+	server, err := banjo.listen()
+	if err != nil {
+		banjo.logger.Critical(fmt.Sprintf("Error while trying to create connection:\nError: %v", err))
+		return err
+	}
+
+	if banjo.config.ProxyProtocol {
+		server = banjo.ProxyListener(server)
+	}
+
+	if banjo.config.TLSRedirectAddr != "" {
+		go banjo.runRedirect()
+	}
+
+	banjo.logger.Info(fmt.Sprintf("BANjO.RUN Started TLS ADDRESS=%v", server.Addr()))
+
+	return banjo.Serve(tls.NewListener(server, config))
+}
+
+// TLS function
+//
+// Returns TLS connection state, nil for plain HTTP requests,
+// verified client certificates are available in PeerCertificates
+//
+// Params:
+// - None
+//
+// Response:
+// - state {*tls.ConnectionState}
+//
+func (ctx *Context) TLS() *tls.ConnectionState {
+	return ctx.tlsState
+}
+
+// ClientCertificate function
+//
+// Returns leaf client certificate for mutual TLS connections
+//
+// Params:
+// - None
+//
+// Response:
+// - cert {*x509.Certificate} nil if client didn't send certificate
+//
+func (ctx *Context) ClientCertificate() *x509.Certificate {
+	if ctx.tlsState == nil || len(ctx.tlsState.PeerCertificates) == 0 {
+		return nil
+	}
+
+	return ctx.tlsState.PeerCertificates[0]
+}
+
+// tlsConfig function
+//
+// Builds *tls.Config from Config.TLSConfig & certificate files
+//
+// Params:
+// - pairs {...TLSCertificate} primary certificates
+//
+// Response:
+// - config {*tls.Config}
+// - err    {error}
+//
+func (banjo Banjo) tlsConfig(pairs ...TLSCertificate) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if banjo.config.TLSConfig != nil {
+		config = banjo.config.TLSConfig.Clone()
+	}
+
+	pairs = append(pairs, banjo.config.TLSCertificates...)
+	if len(pairs) == 0 {
+		if len(config.Certificates) == 0 && config.GetCertificate == nil {
+			return nil, errors.New("tls certificate didn't exist")
+		}
+
+		return config, nil
+	}
+
+	store := &certificateStore{pairs: pairs, logger: banjo.logger}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	interval := banjo.config.TLSReloadInterval
+	if interval <= 0 {
+		interval = DefaultTLSReloadInterval
+	}
+
+	go store.watch(interval)
+
+	config.GetCertificate = store.GetCertificate
+
+	return config, nil
+}
+
+// GetCertificate function
+//
+// Selects certificate by SNI server name,
+// first certificate is used if nothing matches
+//
+// Params:
+// - hello {*tls.ClientHelloInfo}
+//
+// Response:
+// - cert {*tls.Certificate}
+// - err  {error}
+//
+func (store *certificateStore) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	if hello.ServerName != "" {
+		for _, cert := range store.certificates {
+			if cert.Leaf != nil && cert.Leaf.VerifyHostname(hello.ServerName) == nil {
+				return cert, nil
+			}
+		}
+	}
+
+	return store.certificates[0], nil
+}
+
+// load function
+//
+// Loads all certificate pairs, fails if any of them is invalid
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error}
+//
+func (store *certificateStore) load() error {
+	certificates := make([]*tls.Certificate, len(store.pairs))
+	modified := make([]time.Time, len(store.pairs))
+
+	for i, pair := range store.pairs {
+		cert, err := loadCertificate(pair)
+		if err != nil {
+			return err
+		}
+
+		certificates[i] = cert
+		modified[i] = modTime(pair)
+	}
+
+	store.mutex.Lock()
+	store.certificates = certificates
+	store.modified = modified
+	store.mutex.Unlock()
+
+	return nil
+}
+
+// reload function
+//
+// Reloads certificates if any file was changed,
+// keeps previous certificates if new ones are invalid
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (store *certificateStore) reload() {
+	store.mutex.RLock()
+	changed := false
+	for i, pair := range store.pairs {
+		if !modTime(pair).Equal(store.modified[i]) {
+			changed = true
+		}
+	}
+	store.mutex.RUnlock()
+
+	if !changed {
+		return
+	}
+
+	if err := store.load(); err != nil {
+		store.logger.Error(fmt.Sprintf("Error while reloading TLS certificates:\nError: %v", err))
+		return
+	}
+
+	store.logger.Info("TLS certificates reloaded")
+}
+
+// watch function
+//
+// Checks certificate files for changes with given interval
+//
+// Params:
+// - interval {time.Duration}
+//
+// Response:
+// - None
+//
+func (store *certificateStore) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		store.reload()
+	}
+}
+
+// runRedirect function
+//
+// Starts plain HTTP listener on Config.TLSRedirectAddr
+// which redirects all requests to HTTPS
+//
+// Params:
+// - None
+//
+// Response:
+// - None
+//
+func (banjo Banjo) runRedirect() {
+	server, err := net.Listen("tcp", banjo.config.TLSRedirectAddr)
+	if err != nil {
+		banjo.logger.Error(fmt.Sprintf("Error while trying to create redirect connection:\nError: %v", err))
+		return
+	}
+
+	defer server.Close()
+
+	for {
+		conn, err := server.Accept()
+		if err != nil {
+			banjo.logger.Error(fmt.Sprintf("Error while trying to accept incomming connection:\nError: %v", err))
+			continue
+		}
+
+		go banjo.redirectHTTPS(conn)
+	}
+}
+
+// redirectHTTPS function
+//
+// Answers plain HTTP request with redirect to the same URL over HTTPS
+//
+// Params:
+// - conn {net.Conn}
+//
+// Response:
+// - None
+//
+func (banjo Banjo) redirectHTTPS(conn net.Conn) {
+	defer conn.Close()
+
+	data := make([]byte, 2048)
+	n, err := conn.Read(data)
+	if err != nil {
+		return
+	}
+
+	request := banjo.parser.Request(string(data[:n]))
+
+	host := request.Header("Host")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if banjo.config.port != "443" {
+		host = net.JoinHostPort(host, banjo.config.port)
+	}
+
+	response := Response{
+		Status:  301,
+		Headers: map[string]string{"Location": "https://" + host + request.URL},
+	}
+	addRequiredHeaders(&response)
+	response.Headers["Content-Length"] = "0"
+
+	conn.Write([]byte(banjo.parser.Response(response)))
+}
+
+// loadCertificate function
+//
+// Loads certificate pair & parses leaf certificate for SNI matching
+//
+// Params:
+// - pair {TLSCertificate}
+//
+// Response:
+// - cert {*tls.Certificate}
+// - err  {error}
+//
+func loadCertificate(pair TLSCertificate) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(pair.CertFile, pair.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if cert.Leaf == nil {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+
+		cert.Leaf = leaf
+	}
+
+	return &cert, nil
+}
+
+// modTime function
+//
+// Returns latest modification time of certificate pair files
+//
+// Params:
+// - pair {TLSCertificate}
+//
+// Response:
+// - time {time.Time}
+//
+func modTime(pair TLSCertificate) time.Time {
+	var latest time.Time
+
+	for _, path := range []string{pair.CertFile, pair.KeyFile} {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,166 @@
+package banjo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestCertificate(t *testing.T, dir string, name string) TLSCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Key should be generated")
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Certificate should be created")
+	}
+
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+	pair := TLSCertificate{
+		CertFile: filepath.Join(dir, name+".crt"),
+		KeyFile:  filepath.Join(dir, name+".key"),
+	}
+
+	ioutil.WriteFile(pair.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(pair.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+
+	return pair
+}
+
+func TestCertificateStoreSelectsBySNI(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "banjo")
+	defer os.RemoveAll(dir)
+
+	store := &certificateStore{pairs: []TLSCertificate{
+		writeTestCertificate(t, dir, "foo.example"),
+		writeTestCertificate(t, dir, "bar.example"),
+	}}
+
+	if err := store.load(); err != nil {
+		t.Fatalf("Certificates should be loaded")
+	}
+
+	cert, _ := store.GetCertificate(&tls.ClientHelloInfo{ServerName: "bar.example"})
+	if cert.Leaf.Subject.CommonName != "bar.example" {
+		t.Errorf("Certificate should be selected by server name")
+	}
+
+	cert, _ = store.GetCertificate(&tls.ClientHelloInfo{ServerName: "unknown.example"})
+	if cert.Leaf.Subject.CommonName != "foo.example" {
+		t.Errorf("First certificate should be used by default")
+	}
+}
+
+func TestCertificateStoreReloadsChangedFiles(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "banjo")
+	defer os.RemoveAll(dir)
+
+	pair := writeTestCertificate(t, dir, "foo.example")
+	store := &certificateStore{pairs: []TLSCertificate{pair}, logger: CreateLogger()}
+	store.load()
+
+	before, _ := store.GetCertificate(&tls.ClientHelloInfo{})
+
+	writeTestCertificate(t, dir, "foo.example")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(pair.CertFile, later, later)
+	store.reload()
+
+	after, _ := store.GetCertificate(&tls.ClientHelloInfo{})
+	if before.Leaf.SerialNumber.Cmp(after.Leaf.SerialNumber) == 0 {
+		t.Errorf("Certificate should be reloaded")
+	}
+}
+
+func TestMutualTLSClientCertificateOnContext(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "banjo")
+	defer os.RemoveAll(dir)
+
+	serverPair := writeTestCertificate(t, dir, "localhost")
+	clientPair := writeTestCertificate(t, dir, "client")
+	serverCert, _ := loadCertificate(serverPair)
+	clientCert, _ := loadCertificate(clientPair)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert.Leaf)
+	rootPool := x509.NewCertPool()
+	rootPool.AddCert(serverCert.Leaf)
+
+	app := Create(DefaultConfig())
+	commonName := make(chan string, 1)
+	app.Get("/me", func(ctx *Context) {
+		if cert := ctx.ClientCertificate(); cert != nil {
+			commonName <- cert.Subject.CommonName
+		}
+		ctx.HTML("ok")
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(tls.Server(server, &tls.Config{
+		Certificates: []tls.Certificate{*serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}))
+
+	conn := tls.Client(client, &tls.Config{
+		ServerName:   "localhost",
+		RootCAs:      rootPool,
+		Certificates: []tls.Certificate{*clientCert},
+	})
+	conn.Write([]byte("GET /me HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	data, _ := ioutil.ReadAll(conn)
+
+	if !strings.HasPrefix(string(data), "HTTP/1.1 200\r\n") {
+		t.Errorf("Status should be 200")
+	}
+
+	select {
+	case name := <-commonName:
+		if name != "client" {
+			t.Errorf("Client certificate common name should be `client`")
+		}
+	default:
+		t.Errorf("Client certificate should be available on Context")
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	app := Create(DefaultConfig())
+	client, server := net.Pipe()
+	go app.redirectHTTPS(server)
+
+	client.Write([]byte("GET /foo?bar=1 HTTP/1.1\r\nHost: example.com:8080\r\n\r\n"))
+	data, _ := ioutil.ReadAll(client)
+	response := string(data)
+
+	if !strings.HasPrefix(response, "HTTP/1.1 301\r\n") {
+		t.Errorf("Status should be 301")
+	}
+
+	if !strings.Contains(response, "Location: https://example.com:4321/foo?bar=1\r\n") {
+		t.Errorf("Location should point to HTTPS port")
+	}
+}
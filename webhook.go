@@ -0,0 +1,171 @@
+package banjo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWebhookTolerance is default allowed age of signed webhook timestamp
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// WebhookOptions struct
+//
+// Webhook signature verification configuration
+//
+type WebhookOptions struct {
+	// Header contains signature, several signatures
+	// can be separated by comma
+	Header string
+
+	// Prefix is stripped from signature like "sha256="
+	Prefix string
+
+	// Secrets are active signing secrets, signature
+	// made with any of them is accepted
+	Secrets [][]byte
+
+	// Hash is HMAC hash function, sha256.New if nil
+	Hash func() hash.Hash
+
+	// Base64 signature encoding, hex is used by default
+	Base64 bool
+
+	// TimestampHeader contains unix time of signing,
+	// timestamp isn't checked if empty
+	TimestampHeader string
+
+	// Tolerance is allowed timestamp age, DefaultWebhookTolerance if zero
+	Tolerance time.Duration
+
+	// Payload builds signed content, "timestamp.body"
+	// if TimestampHeader is set, raw body otherwise
+	Payload func(timestamp string, body []byte) []byte
+}
+
+// Webhook function
+//
+// Returns middleware verifying HMAC signature of exact raw
+// request body, 403 is sent if signature or timestamp is invalid,
+// compressed bodies are verified as received, before decompression
+// Example usage:
+// github := banjo.Webhook(banjo.WebhookOptions{
+//   Header:  "X-Hub-Signature-256",
+//   Prefix:  "sha256=",
+//   Secrets: [][]byte{[]byte(os.Getenv("GITHUB_SECRET"))},
+// })
+// app.Post("/webhooks/github", banjo.Chain(handle, github))
+//
+// Params:
+// - options {WebhookOptions}
+//
+// Response:
+// - middleware {Middleware}
+//
+func Webhook(options WebhookOptions) Middleware {
+	if options.Hash == nil {
+		options.Hash = sha256.New
+	}
+
+	if options.Tolerance <= 0 {
+		options.Tolerance = DefaultWebhookTolerance
+	}
+
+	return func(next func(ctx *Context)) func(ctx *Context) {
+		return func(ctx *Context) {
+			if !options.verify(ctx.Request, time.Now()) {
+				ctx.Response.Status = http.StatusForbidden
+				ctx.Response.Body = "Forbidden - invalid webhook signature"
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
+
+// verify function
+//
+// Checks request signature & timestamp
+//
+// Params:
+// - request {Request}
+// - now     {time.Time}
+//
+// Response:
+// - ok {bool}
+//
+func (options WebhookOptions) verify(request Request, now time.Time) bool {
+	timestamp := ""
+	body := request.Body
+	if request.RawBody != nil {
+		body = request.RawBody
+	}
+
+	payload := body
+
+	if options.TimestampHeader != "" {
+		timestamp = request.Header(options.TimestampHeader)
+
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false
+		}
+
+		age := now.Sub(time.Unix(seconds, 0))
+		if age > options.Tolerance || age < -options.Tolerance {
+			return false
+		}
+
+		payload = append(append([]byte(timestamp), '.'), body...)
+	}
+
+	if options.Payload != nil {
+		payload = options.Payload(timestamp, body)
+	}
+
+	for _, value := range strings.Split(request.Header(options.Header), ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), options.Prefix)
+
+		signature, err := options.decode(value)
+		if err != nil || len(signature) == 0 {
+			continue
+		}
+
+		for _, secret := range options.Secrets {
+			mac := hmac.New(options.Hash, secret)
+			mac.Write(payload)
+
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// decode function
+//
+// Decodes hex or base64 signature
+//
+// Params:
+// - value {string}
+//
+// Response:
+// - signature {[]byte}
+// - err       {error}
+//
+func (options WebhookOptions) decode(value string) ([]byte, error) {
+	if options.Base64 {
+		return base64.StdEncoding.DecodeString(value)
+	}
+
+	return hex.DecodeString(value)
+}
@@ -0,0 +1,101 @@
+package banjo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func webhookSignature(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookPost(body string, headers map[string]string) Request {
+	return Request{Method: "POST", URL: "/hook", Headers: headers, Body: []byte(body), Params: body}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/hook", Chain(func(ctx *Context) {
+		ctx.HTML("ok")
+	}, Webhook(WebhookOptions{
+		Header:  "X-Hub-Signature-256",
+		Prefix:  "sha256=",
+		Secrets: [][]byte{[]byte("new"), []byte("old")},
+	})))
+
+	body := "{\"action\": \"opened\"}\r\n"
+
+	for _, secret := range []string{"new", "old"} {
+		ctx := testDispatch(app, webhookPost(body, map[string]string{"X-Hub-Signature-256": "sha256=" + webhookSignature(secret, body)}))
+		if ctx.Response.Body != "ok" {
+			t.Errorf("Signature made with active secret should be accepted")
+		}
+	}
+
+	ctx := testDispatch(app, webhookPost(body+" ", map[string]string{"X-Hub-Signature-256": "sha256=" + webhookSignature("new", body)}))
+	if ctx.Response.Status != 403 {
+		t.Errorf("Signature of other body should be rejected")
+	}
+
+	ctx = testDispatch(app, webhookPost(body, map[string]string{"X-Hub-Signature-256": "sha256=" + webhookSignature("revoked", body)}))
+	if ctx.Response.Status != 403 {
+		t.Errorf("Signature made with unknown secret should be rejected")
+	}
+}
+
+func TestWebhookTimestamp(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/hook", Chain(func(ctx *Context) {
+		ctx.HTML("ok")
+	}, Webhook(WebhookOptions{
+		Header:          "X-Signature",
+		Secrets:         [][]byte{[]byte("secret")},
+		TimestampHeader: "X-Timestamp",
+		Tolerance:       time.Minute,
+	})))
+
+	body := "payload"
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	ctx := testDispatch(app, webhookPost(body, map[string]string{
+		"X-Timestamp": now,
+		"X-Signature": "invalid, " + webhookSignature("secret", now+"."+body),
+	}))
+	if ctx.Response.Body != "ok" {
+		t.Errorf("Fresh signed request should be accepted")
+	}
+
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	ctx = testDispatch(app, webhookPost(body, map[string]string{
+		"X-Timestamp": old,
+		"X-Signature": webhookSignature("secret", old+"."+body),
+	}))
+	if ctx.Response.Status != 403 {
+		t.Errorf("Replayed request should be rejected")
+	}
+}
+
+func TestWebhookCompressedBody(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Post("/hook", Chain(func(ctx *Context) {
+		ctx.HTML(ctx.Request.Params)
+	}, Webhook(WebhookOptions{
+		Header:  "X-Signature",
+		Secrets: [][]byte{[]byte("secret")},
+	})))
+
+	body := string(gzipBody("payload"))
+	request := app.parser.Request("POST /hook HTTP/1.1\r\ncontent-encoding: gzip\r\nx-signature: " + webhookSignature("secret", body) + "\r\n\r\n" + body)
+
+	ctx := testDispatch(app, request)
+	if ctx.Response.Status != 200 || ctx.Response.Body != "payload" {
+		t.Errorf("Signature of compressed body should be accepted")
+	}
+}
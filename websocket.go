@@ -0,0 +1,584 @@
+package banjo
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// WebSocket message types
+const (
+	ContinuationMessage = 0
+	TextMessage         = 1
+	BinaryMessage       = 2
+	CloseMessage        = 8
+	PingMessage         = 9
+	PongMessage         = 10
+)
+
+// WebSocket close codes, see RFC 6455 section 7.4.1
+const (
+	CloseNormalClosure       = 1000
+	CloseGoingAway           = 1001
+	CloseProtocolError       = 1002
+	CloseUnsupportedData     = 1003
+	CloseNoStatusReceived    = 1005
+	CloseAbnormalClosure     = 1006
+	CloseInvalidPayload      = 1007
+	ClosePolicyViolation     = 1008
+	CloseMessageTooBig       = 1009
+	CloseInternalServerError = 1011
+)
+
+const (
+	websocketGUID           = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	websocketVersion        = "13"
+	websocketFrameHeaderMax = 14
+	maxControlFramePayload  = 125
+)
+
+// DefaultWebSocketMaxMessageSize is default limit
+// for the size of single incoming WebSocket message
+const DefaultWebSocketMaxMessageSize = 1 << 20
+
+// ErrMessageTooBig is returned by WSConn.ReadMessage
+// when incoming message exceeds configured size limit
+var ErrMessageTooBig = errors.New("websocket message too big")
+
+// CloseError struct
+//
+// Returned by WSConn.ReadMessage when
+// connection was closed by the client
+//
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error function
+//
+// Implements error interface
+//
+// Params:
+// - None
+//
+// Response:
+// - message {string}
+//
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket closed: %d %s", e.Code, e.Reason)
+}
+
+// WSConn struct
+//
+// Upgraded WebSocket connection,
+// passed to closure registered with app.WebSocket
+//
+type WSConn struct {
+	Request Request
+
+	conn           net.Conn
+	reader         *bufio.Reader
+	mutex          sync.Mutex
+	maxMessageSize int64
+	closeSent      bool
+}
+
+// WebSocket function
+//
+// For handling WebSocket connections,
+// performs RFC 6455 handshake for GET request on given url
+// and passes upgraded connection to the closure
+//
+// Params:
+// - url     {string} HTTP Request URL
+// - closure {func(conn *WSConn)} Closure for handling WebSocket connection
+//
+// Response:
+// - None
+//
+func (banjo Banjo) WebSocket(url string, closure func(conn *WSConn)) {
+	config := banjo.config
+
+	banjo.routes.Push("GET", url, func(ctx *Context) {
+		ctx.upgradeWebSocket(config, closure)
+	})
+}
+
+// upgradeWebSocket function
+//
+// Validates handshake request, sends 101 response
+// and runs WebSocket closure over the raw connection
+//
+// Params:
+// - config  {Config}
+// - closure {func(conn *WSConn)}
+//
+// Response:
+// - None
+//
+func (ctx *Context) upgradeWebSocket(config Config, closure func(conn *WSConn)) {
+	request := ctx.Request
+	key := request.Header("Sec-WebSocket-Key")
+
+	if !headerContainsToken(request.Header("Upgrade"), "websocket") ||
+		!headerContainsToken(request.Header("Connection"), "upgrade") || key == "" {
+		ctx.Response.Status = 400
+		ctx.Response.Body = "Bad Request"
+		return
+	}
+
+	if request.Header("Sec-WebSocket-Version") != websocketVersion {
+		ctx.Response.Status = 426
+		ctx.Response.Headers = map[string]string{"Sec-WebSocket-Version": websocketVersion}
+		ctx.Response.Body = "Upgrade Required"
+		return
+	}
+
+	checkOrigin := config.WebSocketCheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+
+	if !checkOrigin(request) {
+		ctx.Response.Status = 403
+		ctx.Response.Body = "Forbidden"
+		return
+	}
+
+	if ctx.conn == nil && !ctx.hijackHTTP() {
+		ctx.Response.Status = 500
+		ctx.Response.Body = "Internal Server Error"
+		return
+	}
+
+	ctx.hijacked = true
+	ctx.Response.Status = 101
+	ctx.clearDeadlines()
+	pending := ctx.stopWatching()
+
+	handshake := Parser{}.Head(Response{
+		Status: 101,
+		Headers: map[string]string{
+			"Upgrade":              "websocket",
+			"Connection":           "Upgrade",
+			"Sec-WebSocket-Accept": websocketAccept(key),
+		},
+	})
+
+	if _, err := ctx.conn.Write([]byte(handshake)); err != nil {
+		return
+	}
+
+	maxSize := config.WebSocketMaxMessageSize
+	if maxSize <= 0 {
+		maxSize = DefaultWebSocketMaxMessageSize
+	}
+
+	ws := &WSConn{
+		Request:        request,
+		conn:           ctx.conn,
+		reader:         bufio.NewReader(io.MultiReader(bytes.NewReader(pending), ctx.conn)),
+		maxMessageSize: maxSize,
+	}
+
+	closure(ws)
+
+	ws.Close(CloseNormalClosure, "")
+}
+
+// ReadMessage function
+//
+// Reads next data message, joining fragmented frames,
+// answers ping frames & close handshake automatically
+//
+// Params:
+// - None
+//
+// Response:
+// - messageType {int}    TextMessage or BinaryMessage
+// - data        {[]byte} message payload
+// - err         {error}  *CloseError when client closed connection
+//
+func (ws *WSConn) ReadMessage() (int, []byte, error) {
+	messageType := 0
+	message := []byte{}
+
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := ws.WriteMessage(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			return 0, nil, ws.handleClose(payload)
+		case ContinuationMessage:
+			if messageType == 0 {
+				return 0, nil, ws.fail(CloseProtocolError, "unexpected continuation frame")
+			}
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, ws.fail(CloseProtocolError, "expected continuation frame")
+			}
+			messageType = opcode
+		default:
+			return 0, nil, ws.fail(CloseProtocolError, "unknown opcode")
+		}
+
+		if int64(len(message)+len(payload)) > ws.maxMessageSize {
+			ws.Close(CloseMessageTooBig, "")
+			return 0, nil, ErrMessageTooBig
+		}
+
+		message = append(message, payload...)
+
+		if fin {
+			break
+		}
+	}
+
+	if messageType == TextMessage && !utf8.Valid(message) {
+		return 0, nil, ws.fail(CloseInvalidPayload, "invalid utf-8")
+	}
+
+	return messageType, message, nil
+}
+
+// WriteMessage function
+//
+// Sends single unfragmented frame to the client
+//
+// Params:
+// - messageType {int}    TextMessage, BinaryMessage, PingMessage or PongMessage
+// - data        {[]byte} message payload
+//
+// Response:
+// - err {error}
+//
+func (ws *WSConn) WriteMessage(messageType int, data []byte) error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.closeSent {
+		return &CloseError{Code: CloseAbnormalClosure, Reason: "connection closed"}
+	}
+
+	return ws.writeFrame(messageType, data)
+}
+
+// WriteText function
+//
+// Sends text message to the client
+//
+// Params:
+// - text {string}
+//
+// Response:
+// - err {error}
+//
+func (ws *WSConn) WriteText(text string) error {
+	return ws.WriteMessage(TextMessage, []byte(text))
+}
+
+// Ping function
+//
+// Sends ping frame to the client, pong reply is
+// consumed by ReadMessage
+//
+// Params:
+// - data {[]byte} application data, up to 125 bytes
+//
+// Response:
+// - err {error}
+//
+func (ws *WSConn) Ping(data []byte) error {
+	if len(data) > maxControlFramePayload {
+		return errors.New("ping payload too big")
+	}
+
+	return ws.WriteMessage(PingMessage, data)
+}
+
+// Close function
+//
+// Sends close frame with given code & reason
+// and closes underlying connection
+//
+// Params:
+// - code   {int}    close code
+// - reason {string} close reason
+//
+// Response:
+// - err {error}
+//
+func (ws *WSConn) Close(code int, reason string) error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.closeSent {
+		return nil
+	}
+
+	ws.closeSent = true
+
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+
+	if len(payload) > maxControlFramePayload {
+		payload = payload[:maxControlFramePayload]
+	}
+
+	err := ws.writeFrame(CloseMessage, payload)
+	ws.conn.Close()
+
+	return err
+}
+
+// RemoteAddr function
+//
+// Returns network address of the client
+//
+// Params:
+// - None
+//
+// Response:
+// - addr {net.Addr}
+//
+func (ws *WSConn) RemoteAddr() net.Addr {
+	return ws.conn.RemoteAddr()
+}
+
+// readFrame function
+//
+// Reads single frame & unmasks its payload
+//
+// Params:
+// - None
+//
+// Response:
+// - fin     {bool}   final fragment flag
+// - opcode  {int}    frame opcode
+// - payload {[]byte} unmasked payload
+// - err     {error}
+//
+func (ws *WSConn) readFrame() (bool, int, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(ws.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7f)
+
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, ws.fail(CloseProtocolError, "reserved bits set")
+	}
+
+	if !masked {
+		return false, 0, nil, ws.fail(CloseProtocolError, "client frame should be masked")
+	}
+
+	if opcode >= CloseMessage && (!fin || length > maxControlFramePayload) {
+		return false, 0, nil, ws.fail(CloseProtocolError, "invalid control frame")
+	}
+
+	switch length {
+	case 126:
+		data := make([]byte, 2)
+		if _, err := io.ReadFull(ws.reader, data); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(data))
+	case 127:
+		data := make([]byte, 8)
+		if _, err := io.ReadFull(ws.reader, data); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(data))
+	}
+
+	if length < 0 || length > ws.maxMessageSize {
+		ws.Close(CloseMessageTooBig, "")
+		return false, 0, nil, ErrMessageTooBig
+	}
+
+	mask := make([]byte, 4)
+	if _, err := io.ReadFull(ws.reader, mask); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame function
+//
+// Writes single final unmasked frame,
+// caller should hold ws.mutex
+//
+// Params:
+// - opcode {int}
+// - data   {[]byte}
+//
+// Response:
+// - err {error}
+//
+func (ws *WSConn) writeFrame(opcode int, data []byte) error {
+	frame := make([]byte, 2, websocketFrameHeaderMax+len(data))
+	frame[0] = 0x80 | byte(opcode)
+
+	switch length := len(data); {
+	case length <= 125:
+		frame[1] = byte(length)
+	case length <= 0xffff:
+		frame[1] = 126
+		frame = append(frame, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame[1] = 127
+		frame = append(frame, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+
+	frame = append(frame, data...)
+	_, err := ws.conn.Write(frame)
+
+	return err
+}
+
+// handleClose function
+//
+// Answers client close frame with the same code
+//
+// Params:
+// - payload {[]byte} close frame payload
+//
+// Response:
+// - err {*CloseError}
+//
+func (ws *WSConn) handleClose(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatusReceived}
+
+	if len(payload) == 1 {
+		ws.Close(CloseProtocolError, "")
+		return closeErr
+	}
+
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+		ws.Close(closeErr.Code, "")
+		return closeErr
+	}
+
+	ws.Close(CloseNormalClosure, "")
+
+	return closeErr
+}
+
+// fail function
+//
+// Closes connection because of protocol violation
+//
+// Params:
+// - code   {int}
+// - reason {string}
+//
+// Response:
+// - err {*CloseError}
+//
+func (ws *WSConn) fail(code int, reason string) error {
+	ws.Close(code, reason)
+	return &CloseError{Code: code, Reason: reason}
+}
+
+// websocketAccept function
+//
+// Computes Sec-WebSocket-Accept value for given key
+//
+// Params:
+// - key {string} Sec-WebSocket-Key header value
+//
+// Response:
+// - accept {string}
+//
+func websocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// sameOrigin function
+//
+// Default origin check, allows requests without Origin
+// header & requests where Origin host matches Host header
+//
+// Params:
+// - request {Request}
+//
+// Response:
+// - ok {bool}
+//
+func sameOrigin(request Request) bool {
+	origin := request.Header("Origin")
+	if origin == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(parsed.Host, request.Header("Host"))
+}
+
+// headerContainsToken function
+//
+// Checks comma separated header value for token,
+// case insensitive
+//
+// Params:
+// - value {string} header value
+// - token {string}
+//
+// Response:
+// - ok {bool}
+//
+func headerContainsToken(value string, token string) bool {
+	for _, item := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), token) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package banjo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func writeClientFrame(conn net.Conn, fin bool, opcode int, data []byte) {
+	header := []byte{byte(opcode), 0x80 | byte(len(data))}
+	if fin {
+		header[0] |= 0x80
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	payload := make([]byte, len(data))
+	for i := range data {
+		payload[i] = data[i] ^ mask[i%4]
+	}
+
+	conn.Write(append(append(header, mask...), payload...))
+}
+
+func readServerFrame(reader *bufio.Reader) (int, []byte) {
+	header := make([]byte, 2)
+	io.ReadFull(reader, header)
+
+	payload := make([]byte, header[1]&0x7f)
+	io.ReadFull(reader, payload)
+
+	return int(header[0] & 0x0f), payload
+}
+
+func openWebSocket(t *testing.T, app Banjo, headers string) (net.Conn, *bufio.Reader, string) {
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /ws HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n" + headers + "\r\n"))
+
+	reader := bufio.NewReader(client)
+	head := ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		head += line
+		if line == Separator {
+			break
+		}
+	}
+
+	return client, reader, head
+}
+
+func TestWebSocketAcceptKey(t *testing.T) {
+	if websocketAccept("dGhlIHNhbXBsZSBub25jZQ==") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Accept key should match RFC 6455 example")
+	}
+}
+
+func TestWebSocketEchoFragmentedMessage(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.WebSocket("/ws", func(conn *WSConn) {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.WriteMessage(messageType, data)
+		conn.ReadMessage()
+	})
+
+	client, reader, head := openWebSocket(t, app, "Origin: http://localhost\r\n")
+	defer client.Close()
+
+	if !strings.HasPrefix(head, "HTTP/1.1 101\r\n") {
+		t.Fatalf("Handshake should respond with 101")
+	}
+
+	if !strings.Contains(head, "Sec-WebSocket-Accept: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=\r\n") {
+		t.Errorf("Handshake should contain accept key")
+	}
+
+	writeClientFrame(client, false, TextMessage, []byte("hel"))
+	writeClientFrame(client, true, ContinuationMessage, []byte("lo"))
+
+	opcode, payload := readServerFrame(reader)
+	if opcode != TextMessage || string(payload) != "hello" {
+		t.Errorf("Server should echo joined text message")
+	}
+
+	writeClientFrame(client, true, PingMessage, []byte("ping"))
+
+	opcode, payload = readServerFrame(reader)
+	if opcode != PongMessage || string(payload) != "ping" {
+		t.Errorf("Server should answer ping with pong")
+	}
+
+	closePayload := make([]byte, 2)
+	binary.BigEndian.PutUint16(closePayload, CloseGoingAway)
+	writeClientFrame(client, true, CloseMessage, closePayload)
+
+	opcode, payload = readServerFrame(reader)
+	if opcode != CloseMessage || binary.BigEndian.Uint16(payload) != CloseGoingAway {
+		t.Errorf("Server should answer close with the same code")
+	}
+}
+
+func TestWebSocketMessageSizeLimit(t *testing.T) {
+	cnf := DefaultConfig()
+	cnf.WebSocketMaxMessageSize = 4
+	app := Create(cnf)
+	result := make(chan error, 1)
+
+	app.WebSocket("/ws", func(conn *WSConn) {
+		_, _, err := conn.ReadMessage()
+		result <- err
+	})
+
+	client, reader, _ := openWebSocket(t, app, "")
+	defer client.Close()
+
+	writeClientFrame(client, true, BinaryMessage, []byte("too big"))
+
+	opcode, payload := readServerFrame(reader)
+	if opcode != CloseMessage || binary.BigEndian.Uint16(payload) != CloseMessageTooBig {
+		t.Errorf("Server should close with 1009")
+	}
+
+	if err := <-result; err != ErrMessageTooBig {
+		t.Errorf("ReadMessage should return ErrMessageTooBig")
+	}
+}
+
+func TestWebSocketRejectsForeignOrigin(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.WebSocket("/ws", func(conn *WSConn) {
+		t.Errorf("Closure should not be called")
+	})
+
+	client, _, head := openWebSocket(t, app, "Origin: http://evil.example\r\n")
+	defer client.Close()
+
+	if !strings.HasPrefix(head, "HTTP/1.1 403\r\n") {
+		t.Errorf("Foreign origin should be rejected with 403")
+	}
+}
@@ -0,0 +1,316 @@
+package banjo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ResponseWriter struct
+//
+// Allows you to stream response body directly
+// to the connection instead of buffering it in Response.Body
+// Response status line & headers are written on first Write call
+//
+type ResponseWriter struct {
+	ctx         *Context
+	buffer      *bufio.Writer
+	target      http.ResponseWriter
+	encoder     bodyEncoder
+	wroteHeader bool
+	chunked     bool
+	failed      bool
+}
+
+// bodyEncoder interface
+//
+// Content encoding applied to streamed body
+//
+type bodyEncoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// writerFunc type is func(data []byte) (int, error) alias
+//
+// Adapter to use function as io.Writer
+//
+type writerFunc func(data []byte) (int, error)
+
+// Write function
+//
+// Implements io.Writer
+//
+// Params:
+// - data {[]byte}
+//
+// Response:
+// - n   {int}
+// - err {error}
+//
+func (f writerFunc) Write(data []byte) (int, error) {
+	return f(data)
+}
+
+// Write function
+//
+// Writes data to the response body, on first call
+// sends status line & headers taken from ctx.Response
+// If Context isn't bound to connection data is appended to Response.Body
+//
+// Params:
+// - data {[]byte} part of response body
+//
+// Response:
+// - n   {int}   number of written bytes
+// - err {error} write error
+//
+func (w *ResponseWriter) Write(data []byte) (int, error) {
+	if w.buffer == nil {
+		w.ctx.Response.Body += string(data)
+		return len(data), nil
+	}
+
+	if !w.wroteHeader {
+		if err := w.writeHeader(); err != nil {
+			w.failed = true
+			return 0, err
+		}
+	}
+
+	var n int
+	var err error
+
+	if w.encoder != nil {
+		n, err = w.encoder.Write(data)
+	} else {
+		n, err = w.write(data)
+	}
+
+	if err != nil {
+		w.failed = true
+	}
+
+	return n, err
+}
+
+// Flush function
+//
+// Sends all buffered data to the client,
+// writes headers if they weren't sent yet
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error} write error
+//
+func (w *ResponseWriter) Flush() error {
+	if w.buffer == nil {
+		return nil
+	}
+
+	if !w.wroteHeader {
+		if err := w.writeHeader(); err != nil {
+			w.failed = true
+			return err
+		}
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			w.failed = true
+			return err
+		}
+	}
+
+	if err := w.buffer.Flush(); err != nil {
+		w.failed = true
+		return err
+	}
+
+	if flusher, ok := w.target.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// Streaming function
+//
+// Returns true if response headers were already sent to the client
+//
+// Params:
+// - None
+//
+// Response:
+// - streaming {bool}
+//
+func (w *ResponseWriter) Streaming() bool {
+	return w.wroteHeader
+}
+
+// write function
+//
+// Writes data to the buffer, using chunked
+// encoding when response length is unknown
+//
+// Params:
+// - data {[]byte} part of response body
+//
+// Response:
+// - n   {int}   number of written bytes
+// - err {error} write error
+//
+func (w *ResponseWriter) write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		if err := w.writeHeader(); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	if !w.chunked {
+		return w.buffer.Write(data)
+	}
+
+	if _, err := fmt.Fprintf(w.buffer, "%x%s", len(data), Separator); err != nil {
+		return 0, err
+	}
+
+	n, err := w.buffer.Write(data)
+	if err != nil {
+		return n, err
+	}
+
+	_, err = w.buffer.WriteString(Separator)
+
+	return n, err
+}
+
+// writeHeader function
+//
+// Writes status line & headers from ctx.Response,
+// chunked encoding used if Content-Length header wasn't set by user,
+// http.ResponseWriter handles body encoding itself
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error} write error
+//
+func (w *ResponseWriter) writeHeader() error {
+	w.wroteHeader = true
+
+	w.ctx.prepareResponse()
+
+	if w.target != nil {
+		copyHTTPHeaders(w.target.Header(), w.ctx.Response)
+		w.target.WriteHeader(w.ctx.Response.Status)
+		return nil
+	}
+
+	if _, ok := w.ctx.Response.Headers["Content-Length"]; !ok {
+		w.ctx.Response.Headers["Transfer-Encoding"] = "chunked"
+		w.chunked = true
+	}
+
+	_, err := w.buffer.WriteString(Parser{}.Head(w.ctx.Response))
+
+	return err
+}
+
+// close function
+//
+// Finishes chunked body & flushes buffered data,
+// failed response isn't finished, so client can detect truncated body
+//
+// Params:
+// - None
+//
+// Response:
+// - err {error} write error
+//
+func (w *ResponseWriter) close() error {
+	if w.buffer == nil || !w.wroteHeader || w.failed {
+		return nil
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Close(); err != nil {
+			return err
+		}
+	}
+
+	if w.target != nil {
+		return w.buffer.Flush()
+	}
+
+	if w.chunked {
+		if _, err := w.buffer.WriteString("0" + DubSeparator); err != nil {
+			return err
+		}
+	}
+
+	return w.buffer.Flush()
+}
+
+// encode function
+//
+// Sets content encoding for the rest of streamed body,
+// should be called before first part of body is written
+//
+// Params:
+// - encoder {func(w io.Writer) bodyEncoder} encoder constructor
+//
+// Response:
+// - None
+//
+func (w *ResponseWriter) encode(encoder func(w io.Writer) bodyEncoder) {
+	w.encoder = encoder(writerFunc(w.write))
+}
+
+// copyStream function
+//
+// Copies reader to writer flushing data after each read,
+// so client receives data as soon as it available,
+// read error marks response as failed
+//
+// Params:
+// - w      {*ResponseWriter}
+// - reader {io.Reader}
+//
+// Response:
+// - err {error}
+//
+func copyStream(w *ResponseWriter, reader io.Reader) error {
+	data := make([]byte, 32*1024)
+
+	for {
+		n, err := reader.Read(data)
+
+		if n > 0 {
+			if _, werr := w.Write(data[:n]); werr != nil {
+				return werr
+			}
+
+			if ferr := w.Flush(); ferr != nil {
+				return ferr
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			w.failed = true
+			return err
+		}
+	}
+}
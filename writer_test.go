@@ -0,0 +1,100 @@
+package banjo
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestWriterWithoutConnectionBuffersBody(t *testing.T) {
+	ctx := &Context{}
+	ctx.Writer().Write([]byte("foo"))
+	ctx.Writer().Write([]byte("bar"))
+
+	if ctx.Response.Body != "foobar" {
+		t.Errorf("Body should be `foobar`")
+	}
+}
+
+func TestStreamWithoutConnectionFunc(t *testing.T) {
+	ctx := &Context{}
+	err := ctx.Stream("text/csv", strings.NewReader("id,name\n1,foo\n"))
+
+	if err != nil {
+		t.Errorf("Stream should not return error")
+	}
+
+	if ctx.Response.Headers["Content-Type"] != "text/csv" {
+		t.Errorf("Content-Type should be text/csv")
+	}
+
+	if ctx.Response.Body != "id,name\n1,foo\n" {
+		t.Errorf("Body should be streamed data")
+	}
+}
+
+func TestStreamedResponseUsesChunkedEncoding(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Get("/export", func(ctx *Context) {
+		ctx.Response.Status = 201
+		ctx.Stream("text/csv", strings.NewReader("id,name\n"))
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /export HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	data, _ := ioutil.ReadAll(client)
+	response := string(data)
+
+	if !strings.HasPrefix(response, "HTTP/1.1 201\r\n") {
+		t.Errorf("Status should be 201")
+	}
+
+	if !strings.Contains(response, "Transfer-Encoding: chunked\r\n") {
+		t.Errorf("Response should be chunked")
+	}
+
+	if !strings.HasSuffix(response, "\r\n\r\n8\r\nid,name\n\r\n0\r\n\r\n") {
+		t.Errorf("Body should be chunked data")
+	}
+}
+
+type failingReader struct {
+	reads int
+}
+
+func (r *failingReader) Read(data []byte) (int, error) {
+	r.reads++
+	if r.reads > 1 {
+		return 0, errors.New("read failed")
+	}
+
+	return copy(data, "id,name\n"), nil
+}
+
+func TestFailedStreamIsNotFinished(t *testing.T) {
+	app := Create(DefaultConfig())
+	app.Get("/export", func(ctx *Context) {
+		if err := ctx.Stream("text/csv", &failingReader{}); err == nil {
+			t.Errorf("Stream should return read error")
+		}
+	})
+
+	client, server := net.Pipe()
+	go app.handleRequest(server)
+
+	client.Write([]byte("GET /export HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	data, _ := ioutil.ReadAll(client)
+	response := string(data)
+
+	if !strings.Contains(response, "8\r\nid,name\n\r\n") {
+		t.Errorf("First chunk should be sent")
+	}
+
+	if strings.HasSuffix(response, "0\r\n\r\n") {
+		t.Errorf("Failed response should not be finished with last chunk")
+	}
+}